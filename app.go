@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+)
+
+// App is an entry in the main menu.
+type App struct {
+	ID          string // short name used to pick the app with -app
+	Name        string
+	Description string                                    // one line for the help screen
+	Usage       string                                    // what the app prints for "help"
+	Group       string                                    // the submenu the app is listed in, or "" for the main menu
+	Run         func(ctx context.Context, env *Env) error // should stop soon after ctx is done
+}
+
+// Env is what an app runs with.
+type Env struct {
+	In       io.Reader
+	Out      io.Writer
+	Log      *slog.Logger // records what the user did; see newLogger
+	Config   Config
+	Theme    Theme
+	Scripted bool     // input is a -script file rather than someone typing
+	Session  *Session // the menu's session, or nil for an app run with -app
+	Quiet    bool     // no session summary on exit
+}
+
+// localName is the app's name in the current locale.
+func (a App) localName() string {
+	return translate("app."+a.ID+".name", a.Name)
+}
+
+// localDescription is the app's description in the current locale.
+func (a App) localDescription() string {
+	return translate("app."+a.ID+".description", a.Description)
+}
+
+// apps holds the registered apps in menu order.
+var apps []App
+
+// Register adds app to the main menu. Apps call it from an init function
+// in their own file, so the menu is numbered in file name order.
+func Register(app App) {
+	apps = append(apps, app)
+}
+
+// findApp returns the registered app with the given ID.
+func findApp(id string) (App, bool) {
+	for _, app := range apps {
+		if app.ID == id {
+			return app, true
+		}
+	}
+	return App{}, false
+}
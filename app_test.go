@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// registerFake registers an app that prints what it was run with, for as
+// long as t runs.
+func registerFake(t *testing.T, app App) {
+	t.Helper()
+	saved := slices.Clone(apps)
+	t.Cleanup(func() { apps = saved })
+	if app.Run == nil {
+		app.Run = func(_ context.Context, env *Env) error {
+			fmt.Fprintln(env.Out, "fake app ran")
+			return nil
+		}
+	}
+	Register(app)
+}
+
+func TestRegister(t *testing.T) {
+	registerFake(t, App{ID: "fake", Name: "Fake App", Description: "does nothing"})
+	app, ok := findApp("fake")
+	if !ok || app.Name != "Fake App" {
+		t.Fatalf(`findApp("fake") = %v, %v`, app, ok)
+	}
+
+	root := buildMenu(defaultConfig.enabledApps())
+	if got := root.Entries[len(root.Entries)-1].Name(); got != "Fake App" {
+		t.Errorf("last entry of the main menu is %q, want the app registered last", got)
+	}
+
+	out := runMenu(t, defaultConfig, menuInput("{Fake App}\n\n{Exit}\n"))
+	checkInOrder(t, out, "Fake App", "Main > Fake App", "fake app ran", "Press Enter", "Exited")
+}
+
+func TestRegisterInGroup(t *testing.T) {
+	registerFake(t, App{ID: "fake", Name: "Fake Tool", Group: "Tools"})
+	out := runMenu(t, defaultConfig, menuInput("{Tools}\n5\n\nb\n{Exit}\n"))
+	checkInOrder(t, out, "Main > Tools", "[5] Fake Tool", "Main > Tools > Fake Tool", "fake app ran", "Exited")
+}
+
+func TestPrintHelp(t *testing.T) {
+	registerFake(t, App{ID: "fake", Name: "Fake App", Description: "does nothing at all"})
+	root := buildMenu(defaultConfig.enabledApps())
+	n := len(root.Entries)
+
+	var out bytes.Buffer
+	printHelp(&out, menuStack{root}, nil)
+	checkInOrder(t, out.String(),
+		"[1] Tools: Calculator, ",
+		fmt.Sprintf("[%d] Fake App: does nothing at all\n", n),
+		fmt.Sprintf("%-12d", n+1),
+	)
+
+	// "?" on the menu prints the same text.
+	checkInOrder(t, runMenu(t, defaultConfig, menuInput("?\n{Exit}\n")), out.String(), "Exited")
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/term"
+)
+
+// Limits on password attempts.
+const (
+	backoffAfter   = 3           // failures before attempts start being delayed
+	lockoutAfter   = 5           // failures before giving up entirely
+	initialBackoff = time.Second // first delay, doubled after every further failure
+)
+
+var (
+	errWrongPassword = newError("auth.bad")
+	errLockedOut     = newError("auth.locked")
+)
+
+// Authenticator checks password attempts against a bcrypt hash and slows
+// down, then stops, repeated failures.
+type Authenticator struct {
+	hash        []byte
+	clock       clock
+	failures    int       // consecutive failed attempts
+	lastFailure time.Time // when the latest of them happened
+}
+
+// NewAuthenticator returns an Authenticator for hash that tells time with c.
+func NewAuthenticator(hash []byte, c clock) *Authenticator {
+	return &Authenticator{hash: hash, clock: c}
+}
+
+// Delay returns how long the next attempt still has to wait.
+func (a *Authenticator) Delay() time.Duration {
+	if a.failures < backoffAfter {
+		return 0
+	}
+	backoff := initialBackoff << (a.failures - backoffAfter)
+	return backoff - a.clock.Now().Sub(a.lastFailure)
+}
+
+// Attempt waits out any backoff and then checks password. It returns
+// errWrongPassword on a mismatch and errLockedOut once there have been
+// too many failures in a row.
+func (a *Authenticator) Attempt(password string) error {
+	if a.failures >= lockoutAfter {
+		return errLockedOut
+	}
+	if d := a.Delay(); d > 0 {
+		a.clock.Sleep(d)
+	}
+	if err := checkPassword(a.hash, password); err != nil {
+		a.failures++
+		a.lastFailure = a.clock.Now()
+		if a.failures >= lockoutAfter {
+			return errLockedOut
+		}
+		return err
+	}
+	a.failures = 0
+	return nil
+}
+
+// login asks for the password stored as a bcrypt hash in path until it is
+// entered correctly or the user is locked out. When path does not exist yet
+// this is the first run, so a new password is set instead.
+func login(r io.Reader, w io.Writer, path string) error {
+	hash, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return setPassword(r, w, path)
+	}
+	if err != nil {
+		return err
+	}
+
+	auth := NewAuthenticator(hash, realClock{})
+	for {
+		fmt.Fprintln(w, T("auth.enter"))
+		password, err := readPassword(r, w)
+		if err != nil {
+			return err
+		}
+		if d := auth.Delay(); d > 0 {
+			fmt.Fprintf(w, T("auth.waiting")+"\n", d.Round(time.Second))
+		}
+		err = auth.Attempt(password)
+		if err != errWrongPassword {
+			return err
+		}
+		fmt.Fprintln(w, T("auth.wrong"))
+	}
+}
+
+// readPassword reads a password from r. When stdin and w are terminals it
+// is read in raw mode through a term.Terminal, like a termEditor reads
+// lines, so what is typed is not echoed and Ctrl+C ends the read like the
+// end of input.
+func readPassword(r io.Reader, w io.Writer) (string, error) {
+	out, ok := w.(*os.File)
+	stdin := int(os.Stdin.Fd())
+	if !ok || !term.IsTerminal(stdin) || !term.IsTerminal(int(out.Fd())) {
+		return readLine(r)
+	}
+	state, err := term.MakeRaw(stdin)
+	if err != nil {
+		return readLine(r)
+	}
+	defer term.Restore(stdin, state)
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{r, w}, "")
+	return t.ReadPassword("")
+}
+
+// checkPassword reports whether password matches the bcrypt hash.
+func checkPassword(hash []byte, password string) error {
+	if bcrypt.CompareHashAndPassword(hash, []byte(password)) != nil {
+		return errWrongPassword
+	}
+	return nil
+}
+
+// setPassword asks for a new password until one satisfies the default
+// policy and is confirmed, then stores its hash in path. The plaintext is
+// never written anywhere.
+func setPassword(r io.Reader, w io.Writer, path string) error {
+	fmt.Fprintln(w, T("auth.none"))
+	for {
+		fmt.Fprintln(w, T("auth.choose"))
+		password, err := readPassword(r, w)
+		if err != nil {
+			return err
+		}
+		if !reportPasswordProblems(w, password) {
+			continue
+		}
+		fmt.Fprintln(w, T("auth.confirm"))
+		confirm, err := readPassword(r, w)
+		if err != nil {
+			return err
+		}
+		if confirm != password {
+			fmt.Fprintln(w, T("auth.mismatch"))
+			continue
+		}
+
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, hash, 0o600); err != nil {
+			return err
+		}
+		fmt.Fprintln(w, T("auth.saved"))
+		return nil
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// writeHash stores the hash of password in a new passwd file and returns
+// its path.
+func writeHash(t *testing.T, password string) string {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "passwd")
+	if err := os.WriteFile(path, hash, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLogin(t *testing.T) {
+	path := writeHash(t, "GoodPass1")
+	var out bytes.Buffer
+	if err := login(strings.NewReader("GoodPass1\n"), &out, path); err != nil {
+		t.Fatalf("login with the right password: %v", err)
+	}
+	if strings.Contains(out.String(), T("auth.wrong")) {
+		t.Errorf("the right password was reported as wrong:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := login(strings.NewReader("badpass\nGoodPass1\n"), &out, path); err != nil {
+		t.Fatalf("login after a wrong password: %v", err)
+	}
+	if got := strings.Count(out.String(), T("auth.wrong")); got != 1 {
+		t.Errorf("wrong password reported %d times, want 1:\n%s", got, out.String())
+	}
+}
+
+func TestCheckPassword(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("GoodPass1"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkPassword(hash, "GoodPass1"); err != nil {
+		t.Errorf("checkPassword(right) = %v, want nil", err)
+	}
+	for _, pw := range []string{"", "goodpass1", "GoodPass1 "} {
+		if err := checkPassword(hash, pw); err != errWrongPassword {
+			t.Errorf("checkPassword(%q) = %v, want errWrongPassword", pw, err)
+		}
+	}
+}
+
+func TestAuthenticatorBackoff(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("GoodPass1"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newFakeClock()
+	auth := NewAuthenticator(hash, c)
+
+	// The first failures are not delayed; after backoffAfter of them the
+	// delay starts at initialBackoff and doubles.
+	wantDelays := []time.Duration{0, 0, 0, time.Second, 2 * time.Second}
+	for i, want := range wantDelays {
+		if got := auth.Delay(); got != want {
+			t.Errorf("before attempt %d: Delay() = %v, want %v", i+1, got, want)
+		}
+		err := auth.Attempt("wrong")
+		if i < len(wantDelays)-1 && err != errWrongPassword {
+			t.Errorf("attempt %d: err = %v, want errWrongPassword", i+1, err)
+		}
+		if i == len(wantDelays)-1 && err != errLockedOut {
+			t.Errorf("attempt %d: err = %v, want errLockedOut", i+1, err)
+		}
+	}
+	if want := []time.Duration{time.Second, 2 * time.Second}; !slices.Equal(c.slept, want) {
+		t.Errorf("slept %v, want %v", c.slept, want)
+	}
+
+	// Once locked out, even the right password is refused without waiting.
+	if err := auth.Attempt("GoodPass1"); err != errLockedOut {
+		t.Errorf("right password after the lockout: err = %v, want errLockedOut", err)
+	}
+	if len(c.slept) != 2 {
+		t.Errorf("slept again after the lockout: %v", c.slept)
+	}
+	if got, want := errLockedOut.Error(), "Too many attempts, locked out"; got != want {
+		t.Errorf("lockout message = %q, want %q", got, want)
+	}
+}
+
+func TestAuthenticatorWaitedOut(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("GoodPass1"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newFakeClock()
+	auth := NewAuthenticator(hash, c)
+	for range backoffAfter {
+		auth.Attempt("wrong")
+	}
+
+	// Time already spent since the last failure counts toward the delay.
+	c.Advance(400 * time.Millisecond)
+	if got, want := auth.Delay(), 600*time.Millisecond; got != want {
+		t.Errorf("Delay() = %v, want %v", got, want)
+	}
+	c.Advance(time.Second)
+	if got := auth.Delay(); got > 0 {
+		t.Errorf("Delay() = %v after waiting it out, want none", got)
+	}
+
+	// The right password resets the count of failures.
+	if err := auth.Attempt("GoodPass1"); err != nil {
+		t.Fatalf("right password: %v", err)
+	}
+	if got := auth.Delay(); got != 0 {
+		t.Errorf("Delay() = %v after a success, want 0", got)
+	}
+	if len(c.slept) != 0 {
+		t.Errorf("slept %v, want no sleeping", c.slept)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"math"
+	"math/big"
+)
+
+// errNotInteger means an expression cannot be computed exactly with
+// integers, so big mode falls back to floats.
+var errNotInteger = newError("eval.not_integer")
+
+// Limits that keep big mode from exhausting memory.
+const (
+	maxFactorial = 10000   // largest n for n! and fact(n)
+	maxBigBits   = 1 << 20 // largest result of ^ and <<, in bits
+)
+
+// evaluateBig computes expr exactly with big integers. It returns
+// errNotInteger if any part of expr needs fractions.
+func evaluateBig(expr string, e *env) (_ *big.Int, err error) {
+	defer recoverEval(&err)
+	n, err := parse(expr, e.continues)
+	if err != nil {
+		return nil, err
+	}
+	return n.evalBig(e)
+}
+
+// floatToBig converts a whole float64 to a big.Int.
+func floatToBig(v float64) (*big.Int, error) {
+	if v != math.Trunc(v) || math.IsInf(v, 0) {
+		return nil, errNotInteger
+	}
+	i, _ := big.NewFloat(v).Int(nil)
+	return i, nil
+}
+
+func (n numberNode) evalBig(e *env) (*big.Int, error) {
+	base := 10 // so that 012 means twelve, as it does in float mode
+	if hasBasePrefix(n.text) {
+		base = 0
+	}
+	i, ok := new(big.Int).SetString(n.text, base)
+	if !ok {
+		return nil, errNotInteger
+	}
+	return i, nil
+}
+
+func (n identNode) evalBig(e *env) (*big.Int, error) {
+	if n == "ans" && e.ansExact != nil {
+		return new(big.Int).Set(e.ansExact), nil
+	}
+	v, err := n.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	return floatToBig(v)
+}
+
+func (n assignNode) evalBig(e *env) (*big.Int, error) {
+	i, err := operand(n.x).evalBig(e)
+	if err != nil {
+		return nil, err
+	}
+	// Variables hold float64s, so very large values are rounded.
+	v, _ := new(big.Float).SetInt(i).Float64()
+	return i, e.vars.Set(n.name, v)
+}
+
+func (n callNode) evalBig(e *env) (*big.Int, error) {
+	if n.name != "fact" {
+		return nil, errNotInteger
+	}
+	x, err := operand(n.arg).evalBig(e)
+	if err != nil {
+		return nil, err
+	}
+	return bigFactorial(x)
+}
+
+// bigFactorial computes n! for 0 <= n <= maxFactorial.
+func bigFactorial(n *big.Int) (*big.Int, error) {
+	if n.Sign() < 0 {
+		return nil, newError("eval.fact_whole", n)
+	}
+	if n.Cmp(big.NewInt(maxFactorial)) > 0 {
+		return nil, newError("eval.fact_too_large", n, maxFactorial)
+	}
+	return new(big.Int).MulRange(1, n.Int64()), nil
+}
+
+func (n unaryNode) evalBig(e *env) (*big.Int, error) {
+	x, err := operand(n.x).evalBig(e)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "-" {
+		x.Neg(x)
+	}
+	return x, nil
+}
+
+func (n binaryNode) evalBig(e *env) (*big.Int, error) {
+	a, err := operand(n.left).evalBig(e)
+	if err != nil {
+		return nil, err
+	}
+	b, err := operand(n.right).evalBig(e)
+	if err != nil {
+		return nil, err
+	}
+
+	z := new(big.Int)
+	switch n.op {
+	case "+":
+		return z.Add(a, b), nil
+	case "-":
+		return z.Sub(a, b), nil
+	case "*":
+		return z.Mul(a, b), nil
+	case "/":
+		if b.Sign() == 0 {
+			return nil, errDivideByZero
+		}
+		// Only exact division stays in big mode.
+		if _, rem := z.QuoRem(a, b, new(big.Int)); rem.Sign() != 0 {
+			return nil, errNotInteger
+		}
+		return z, nil
+	case "^":
+		if b.Sign() < 0 {
+			return nil, errNotInteger
+		}
+		// Compared by dividing, as multiplying could overflow for huge b.
+		if a.CmpAbs(big.NewInt(1)) > 0 && b.Cmp(big.NewInt(maxBigBits/int64(a.BitLen()))) > 0 {
+			return nil, newError("eval.pow_too_large", a, b)
+		}
+		return z.Exp(a, b, nil), nil
+	case "&":
+		return z.And(a, b), nil
+	case "|":
+		return z.Or(a, b), nil
+	case "xor":
+		return z.Xor(a, b), nil
+	case "<<", ">>":
+		if b.Sign() < 0 {
+			return nil, newError("eval.negative_shift", b)
+		}
+		if !b.IsInt64() || b.Int64() > maxBigBits {
+			return nil, newError("eval.shift_too_large", b)
+		}
+		if n.op == "<<" {
+			return z.Lsh(a, uint(b.Int64())), nil
+		}
+		return z.Rsh(a, uint(b.Int64())), nil
+	}
+	return nil, newError("eval.unknown_op", n.op)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestEvaluateBig(t *testing.T) {
+	fact50, _ := new(big.Int).SetString("30414093201713378043612608166064768844377641568960512000000000000", 10)
+	tests := []struct {
+		expr string
+		want *big.Int
+	}{
+		{"2^200", new(big.Int).Lsh(big.NewInt(1), 200)},
+		{"50!", fact50},
+		{"fact(50)", fact50},
+		{"2^64 - 1", new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 64), big.NewInt(1))},
+		{"(2^100) / (2^98)", big.NewInt(4)},
+		{"1 << 100 >> 99", big.NewInt(2)},
+		{"0x10 * 012", big.NewInt(192)},
+		{"1^(2^62)", big.NewInt(1)},
+		{"(-1)^(2^62 + 1)", big.NewInt(-1)},
+	}
+	for _, tt := range tests {
+		got, err := evaluateBig(tt.expr, &env{vars: NewSymbolTable()})
+		if err != nil {
+			t.Errorf("evaluateBig(%q) failed: %v", tt.expr, err)
+			continue
+		}
+		if got.Cmp(tt.want) != 0 {
+			t.Errorf("evaluateBig(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateBigNotInteger(t *testing.T) {
+	for _, expr := range []string{"5/2", "2^-1", "1.5 * 2", "sqrt(4)", "2^0.5"} {
+		if _, err := evaluateBig(expr, &env{vars: NewSymbolTable()}); !errors.Is(err, errNotInteger) {
+			t.Errorf("evaluateBig(%q) error = %v, want errNotInteger", expr, err)
+		}
+	}
+
+	// The calculator falls back to floats for them.
+	c := NewCalculator()
+	c.bigMode = true
+	got, fellBack, err := c.Calculate("5/2")
+	if err != nil || got != "2.5" || !fellBack {
+		t.Errorf("Calculate(5/2) in big mode = %q, %v, %v; want 2.5 after falling back", got, fellBack, err)
+	}
+}
+
+func TestEvaluateBigLimits(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"2^(2^21)", "result of 2 ^ 2097152 is too large"},
+		// a.BitLen() * b overflows int64 for these.
+		{"2^(2^62)", "result of 2 ^ 4611686018427387904 is too large"},
+		{"4^(2^61)", "result of 4 ^ 2305843009213693952 is too large"},
+		{"2^(2^64)", "result of 2 ^ 18446744073709551616 is too large"},
+		{"fact(10001)", "factorial of 10001 is too large (the limit is 10000)"},
+		{"1 << (2^21)", "shift count 2097152 is too large"},
+		{"1 << -1", "negative shift count -1"},
+		{"1 / 0", "cannot divide by zero"},
+	}
+	for _, tt := range tests {
+		_, err := evaluateBig(tt.expr, &env{vars: NewSymbolTable()})
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("evaluateBig(%q) error = %v, want %q", tt.expr, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,573 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	Register(App{
+		ID:          "calculator",
+		Name:        "Calculator",
+		Description: "evaluate expressions, with variables, memory and exact integers",
+		Usage:       calculatorHelp,
+		Group:       "Tools",
+		Run:         runCalculator,
+	})
+}
+
+// calculatorHelp is the calculator's usage, printed by its "help" command.
+const calculatorHelp = `Type an expression such as 2 + 3 * (4 - 1) to evaluate it.
+Start a line with an operator, as in "+ 20" or "- 5", to continue from the last result.
+Operators: + - * / ^ ! % & | xor << >>  (15% is 0.15, so 200 * 15% is 30)
+Functions: sqrt sin cos tan asin acos atan log ln exp fact
+Names: pi, e, ans (the previous result), MR (the memory) and variables set with x = 5
+Commands:
+  MS, M+, M-, MC       store, add or subtract the last result in memory, or clear it
+  vars                 list the variables and their values
+  undo, redo           take back the latest assignment or memory change, or make it again
+  history              list the calculations so far
+  export <file>        write the history to a CSV file
+  as hex|oct|bin|dec   choose the base results are shown in
+  mode big|float       exact integer arithmetic or floating point
+  mode deg|rad         angles in degrees or radians (the prompt shows which)
+  format n|auto        show results with n decimal places, or as many as needed
+  format group on|off  separate the thousands in results
+  format               show the current format
+  clear                forget the lines the up arrow recalls
+  help                 show this text
+  exit, q              back to the menu`
+
+// calculatorCommands are the commands of the calculator's prompt, suggested
+// when a line that is only a mistyped one fails as an undefined name.
+var calculatorCommands = []string{
+	"MS", "M+", "M-", "MC", "vars", "undo", "redo", "history", "export",
+	"as", "mode", "format", "clear", "help", "exit", "q",
+}
+
+var errNoResult = newError("calc.no_result")
+
+// Calculator is the state of one calculator session.
+type Calculator struct {
+	vars      *SymbolTable
+	base      int     // set with "as hex" and friends
+	bigMode   bool    // set with "mode big" and "mode float"
+	degrees   bool    // set with "mode deg" and "mode rad"
+	precision int     // decimal places shown, or -1 for as many as needed; set with "format n"
+	group     bool    // group thousands; set with "format group on"
+	memory    float64 // the M register
+	last      float64 // the latest result
+	hasResult bool
+	changes   UndoStack[calcState]
+}
+
+// calcState is the part of a Calculator that undo and redo restore.
+type calcState struct {
+	vars   *SymbolTable
+	memory float64
+}
+
+// snapshot returns a copy of the variables and memory.
+func (c *Calculator) snapshot() calcState {
+	return calcState{vars: c.vars.clone(), memory: c.memory}
+}
+
+// record is deferred with a snapshot taken before a change, and makes the
+// change undoable if there was one.
+func (c *Calculator) record(before calcState) {
+	if !before.vars.equal(c.vars) || before.memory != c.memory {
+		c.changes.Push(before)
+	}
+}
+
+// Undo reverts the latest assignment or change to memory that has not been
+// undone.
+func (c *Calculator) Undo() error {
+	s, err := c.changes.Undo(c.snapshot())
+	if err != nil {
+		return err
+	}
+	c.vars, c.memory = s.vars, s.memory
+	return nil
+}
+
+// Redo makes again the change the latest Undo reverted.
+func (c *Calculator) Redo() error {
+	s, err := c.changes.Redo(c.snapshot())
+	if err != nil {
+		return err
+	}
+	c.vars, c.memory = s.vars, s.memory
+	return nil
+}
+
+// NewCalculator returns a Calculator in float mode showing decimal results.
+func NewCalculator() *Calculator {
+	return &Calculator{vars: NewSymbolTable(), base: 10, precision: -1}
+}
+
+// MemoryStore replaces the memory with the latest result.
+func (c *Calculator) MemoryStore() error {
+	defer c.record(c.snapshot())
+	if !c.hasResult {
+		return errNoResult
+	}
+	c.memory = c.last
+	return nil
+}
+
+// MemoryAdd adds the latest result to the memory.
+func (c *Calculator) MemoryAdd() error {
+	defer c.record(c.snapshot())
+	if !c.hasResult {
+		return errNoResult
+	}
+	c.memory += c.last
+	return nil
+}
+
+// MemorySubtract subtracts the latest result from the memory.
+func (c *Calculator) MemorySubtract() error {
+	defer c.record(c.snapshot())
+	if !c.hasResult {
+		return errNoResult
+	}
+	c.memory -= c.last
+	return nil
+}
+
+// MemoryRecall returns the value in memory.
+func (c *Calculator) MemoryRecall() float64 {
+	return c.memory
+}
+
+// MemoryClear sets the memory back to zero.
+func (c *Calculator) MemoryClear() {
+	defer c.record(c.snapshot())
+	c.memory = 0
+}
+
+// memoryCommand runs cmd if it is one of MS, M+, M- or MC, in any case,
+// and reports whether it was.
+func (c *Calculator) memoryCommand(cmd string) (bool, error) {
+	switch strings.ToUpper(cmd) {
+	case "MS":
+		return true, c.MemoryStore()
+	case "M+":
+		return true, c.MemoryAdd()
+	case "M-":
+		return true, c.MemorySubtract()
+	case "MC":
+		c.MemoryClear()
+		return true, nil
+	}
+	return false, nil
+}
+
+// prompt returns the REPL prompt, which shows the angle mode and is marked
+// with an M while memory is in use.
+func (c *Calculator) prompt() string {
+	angles := "rad"
+	if c.degrees {
+		angles = "deg"
+	}
+	if c.memory != 0 {
+		return "M " + angles + "> "
+	}
+	return angles + "> "
+}
+
+// env returns what expressions are evaluated against: the variables, the
+// memory and, as ans, the latest result in the history.
+func (c *Calculator) env() *env {
+	e := &env{vars: c.vars, memory: c.memory, degrees: c.degrees}
+	h := GetHistory()
+	if len(h) == 0 {
+		return e
+	}
+	last := h[len(h)-1]
+	e.ans, e.hasAns = last.Result, true
+	if exact, ok := new(big.Int).SetString(last.Exact, 10); ok {
+		e.ansExact = exact
+		e.ans, _ = new(big.Float).SetInt(exact).Float64()
+	}
+	return e
+}
+
+// Calculate evaluates expr, records it in the history and returns the
+// result formatted in the current base. fellBack reports that big mode
+// could not compute expr exactly and floats were used instead.
+func (c *Calculator) Calculate(expr string) (result string, fellBack bool, err error) {
+	defer c.record(c.snapshot())
+	e := c.env()
+	recorded := expr // as the history shows it
+	if continuesResult(expr) {
+		if !c.hasResult {
+			return "", false, newError("calc.no_continue")
+		}
+		// Parsed as if ans came first, so error positions still point
+		// into the line as typed.
+		e.continues = true
+		recorded = "ans " + expr
+	}
+
+	if c.bigMode {
+		i, err := evaluateBig(expr, e)
+		if err == nil {
+			AppendBigHistory(recorded, i)
+			c.last, _ = new(big.Float).SetInt(i).Float64()
+			c.hasResult = true
+			if c.base == 10 && c.group {
+				return groupDigits(i.String()), false, nil
+			}
+			return formatBig(i, c.base), false, nil
+		}
+		if !errors.Is(err, errNotInteger) {
+			return "", false, err
+		}
+		fellBack = true
+	}
+	v, err := evaluate(expr, e)
+	if err != nil {
+		return "", fellBack, err
+	}
+	AppendHistory(recorded, v)
+	c.last, c.hasResult = v, true
+	if c.base == 10 {
+		return c.formatResult(v), fellBack, nil
+	}
+	return formatInBase(v, c.base), fellBack, nil
+}
+
+// runCalculator runs the calculator REPL, with line editing when the input
+// and output are a terminal.
+func runCalculator(ctx context.Context, env *Env) error {
+	var ed lineEditor = &plainEditor{env.In, env.Out, "> "}
+	if !env.Scripted {
+		ed = newLineEditor(env.In, env.Out, "> ")
+	}
+	fmt.Fprintln(ed, T("calc.welcome"))
+	c := NewCalculator()
+	c.bigMode = env.Config.CalculatorMode == "big"
+	return calculatorREPL(ctx, ed, c, env)
+}
+
+// calculatorREPL runs lines from ed against c until the user types "exit"
+// or "q" or the input ends, logging each calculation to env.Log. A bad
+// expression is reported and the loop goes on; only a read error other than
+// the end of the input, or ctx.Err() once ctx is done, is returned. A
+// calculation is not interrupted part way: Ctrl+C while one runs cancels
+// ctx, and the loop stops once it has finished.
+func calculatorREPL(ctx context.Context, ed lineEditor, c *Calculator, env *Env) error {
+	results := newResultWriter(false, ed, ed)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err // Ctrl+C while the last line was calculated
+		}
+		ed.SetPrompt(env.Theme.prompt(c.prompt()))
+		line, err := ed.ReadLine()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+
+		switch line {
+		case "":
+			continue
+		case "exit", "q":
+			return nil
+		case "help":
+			fmt.Fprintln(ed, translate("app.calculator.usage", calculatorHelp))
+			continue
+		case "clear":
+			ed.ClearHistory()
+			continue
+		case "history":
+			printHistory(ed)
+			continue
+		case "vars":
+			printVars(ed, c.vars)
+			continue
+		case "undo", "redo":
+			step := c.Undo
+			if line == "redo" {
+				step = c.Redo
+			}
+			if err := step(); err != nil {
+				printError(ed, err)
+			}
+			continue
+		}
+		if ok, err := c.memoryCommand(line); ok {
+			if err != nil {
+				printError(ed, err)
+			}
+			continue
+		}
+		if name, ok := strings.CutPrefix(line, "as "); ok {
+			b, ok := bases[strings.TrimSpace(name)]
+			if !ok {
+				printError(ed, newError("calc.expected_base"))
+				continue
+			}
+			c.base = b
+			continue
+		}
+		if path, ok := strings.CutPrefix(line, "export"); ok && (path == "" || path[0] == ' ') {
+			h := GetHistory()
+			path = strings.TrimSpace(path)
+			if path == "" {
+				printError(ed, newError("export.usage"))
+				continue
+			}
+			if err := writeCSV(path, historyCSVHeader, historyRows(h)); err != nil {
+				printError(ed, err)
+				continue
+			}
+			fmt.Fprintf(ed, T("export.done")+"\n", len(h), path)
+			continue
+		}
+		if arg, ok := strings.CutPrefix(line, "format"); ok && (arg == "" || arg[0] == ' ') {
+			arg = strings.Join(strings.Fields(arg), " ")
+			if arg == "" {
+				printFormat(ed, c)
+				continue
+			}
+			if err := c.setFormat(arg); err != nil {
+				printError(ed, err)
+			}
+			continue
+		}
+		if mode, ok := strings.CutPrefix(line, "mode "); ok {
+			switch strings.TrimSpace(mode) {
+			case "big":
+				c.bigMode = true
+			case "float":
+				c.bigMode = false
+			case "deg":
+				c.degrees = true
+			case "rad":
+				c.degrees = false
+			default:
+				printError(ed, newError("calc.expected_mode"))
+			}
+			continue
+		}
+
+		progress := startProgress(os.Stderr) // a spinner for a big factorial or power
+		result, fellBack, err := c.Calculate(line)
+		progress.Stop()
+		if fellBack {
+			fmt.Fprintln(ed, T("warning"), T("calc.fell_back"))
+		}
+		if err != nil {
+			env.Log.Info("evaluated", "expr", line, "error", err.Error())
+			if isUndefined(err, line) {
+				if _, ok := nearest(line, calculatorCommands); ok {
+					err = unknownCommand(line, calculatorCommands)
+				}
+			}
+			results.WriteError(line, err)
+			continue
+		}
+		env.Log.Info("evaluated", "expr", line, "result", result)
+		env.Session.calculated()
+		results.WriteResult(line, result)
+	}
+}
+
+// isUndefined reports whether err is the error for an undefined variable
+// called name.
+func isUndefined(err error, name string) bool {
+	var me *msgError
+	return errors.As(err, &me) && me.key == "eval.undefined" && me.args[0] == name
+}
+
+// continuesResult reports whether line starts with a binary operator and so
+// applies to the previous result, like "+ 20" on a pocket calculator. A
+// minus only counts when followed by a space, so that -5 is still negative
+// five. A percent sign does not count: it only comes after a number.
+func continuesResult(line string) bool {
+	if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "xor ") {
+		return true
+	}
+	return line != "" && strings.ContainsRune("+*/^&|<>", rune(line[0]))
+}
+
+// bases are the output bases "as" accepts.
+var bases = map[string]int{"hex": 16, "oct": 8, "bin": 2, "dec": 10}
+
+// basePrefixes are the literal prefixes for bases other than 10.
+var basePrefixes = map[int]string{16: "0x", 8: "0o", 2: "0b"}
+
+// formatResult formats a decimal result with the precision and grouping
+// chosen with "format". By default it is shown as briefly as possible, as
+// fmt.Sprint does.
+func (c *Calculator) formatResult(v float64) string {
+	if c.precision < 0 && !c.group {
+		return fmt.Sprint(v)
+	}
+	s := strconv.FormatFloat(v, 'f', -1, 64)
+	if c.precision >= 0 {
+		s = roundDecimal(s, c.precision)
+	}
+	if strings.Trim(s, "-0.") == "" {
+		s = strings.TrimPrefix(s, "-") // rounded to zero: no "-0.00"
+	}
+	if c.group {
+		s = groupDigits(s)
+	}
+	return s
+}
+
+// roundDecimal rounds the decimal number s to places decimal places,
+// padding with zeros if it has fewer. Halves round away from zero, as on a
+// pocket calculator, so 2.5 rounds to 3 and 1.005, which strconv writes as
+// such, to 1.01.
+func roundDecimal(s string, places int) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	frac += strings.Repeat("0", max(0, places-len(frac)))
+	digits := []byte(whole + frac[:places])
+	if len(frac) > places && frac[places] >= '5' {
+		i := len(digits) - 1
+		for ; i >= 0 && digits[i] == '9'; i-- {
+			digits[i] = '0'
+		}
+		if i >= 0 {
+			digits[i]++
+		} else {
+			digits = append([]byte{'1'}, digits...)
+		}
+	}
+	n := len(digits) - places
+	if places == 0 {
+		return sign + string(digits)
+	}
+	return sign + string(digits[:n]) + "." + string(digits[n:])
+}
+
+// groupDigits separates the thousands in the decimal number s and uses the
+// locale's decimal point, for example 1234567.5 as 1,234,567.5.
+func groupDigits(s string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, d := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(T("number.group"))
+		}
+		b.WriteRune(d)
+	}
+	if hasFrac {
+		b.WriteString(T("number.decimal"))
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+// setFormat applies the argument of a "format" command: a number of
+// decimal places, "auto" for as many as needed, or "group on" or "group
+// off".
+func (c *Calculator) setFormat(arg string) error {
+	switch arg {
+	case "auto":
+		c.precision = -1
+		return nil
+	case "group on":
+		c.group = true
+		return nil
+	case "group off":
+		c.group = false
+		return nil
+	}
+	n, err := parseInt(arg, 0, maxPrecision)
+	if err != nil {
+		return newError("calc.expected_format", maxPrecision)
+	}
+	c.precision = n
+	return nil
+}
+
+// maxPrecision is the most decimal places "format" accepts.
+const maxPrecision = 20
+
+// formatInBase formats v like a literal in base, for example 15 as 0xF.
+// Fractions are always shown in decimal.
+func formatInBase(v float64, base int) string {
+	i, err := floatToBig(v)
+	if base == 10 || err != nil {
+		return fmt.Sprint(v)
+	}
+	return formatBig(i, base)
+}
+
+// formatBig formats i like a literal in base.
+func formatBig(i *big.Int, base int) string {
+	if base == 10 {
+		return i.String()
+	}
+	sign := ""
+	if i.Sign() < 0 {
+		sign = "-"
+	}
+	return sign + basePrefixes[base] + strings.ToUpper(new(big.Int).Abs(i).Text(base))
+}
+
+// printFormat shows the settings "format" changes.
+func printFormat(w io.Writer, c *Calculator) {
+	precision := "auto"
+	if c.precision >= 0 {
+		precision = strconv.Itoa(c.precision)
+	}
+	group := "off"
+	if c.group {
+		group = "on"
+	}
+	fmt.Fprintf(w, "format %s, group %s\n", precision, group)
+}
+
+// printVars lists the variables in vars with their values.
+func printVars(w io.Writer, vars *SymbolTable) {
+	names := vars.Names()
+	if len(names) == 0 {
+		fmt.Fprintln(w, T("calc.no_vars"))
+		return
+	}
+	for _, name := range names {
+		v, _ := vars.Get(name)
+		fmt.Fprintf(w, "%s = %v\n", name, v)
+	}
+}
+
+// printHistory lists the calculations made so far, numbered from 1.
+func printHistory(w io.Writer) {
+	h := GetHistory()
+	if len(h) == 0 {
+		fmt.Fprintln(w, T("calc.no_history"))
+		return
+	}
+	for i, entry := range h {
+		result := fmt.Sprint(entry.Result)
+		if entry.Exact != "" {
+			result = entry.Exact
+		}
+		fmt.Fprintf(w, "%d: %s = %s\n", i+1, entry.Expr, result)
+	}
+}
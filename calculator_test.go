@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// calculate runs the calculator REPL on input, one command or expression
+// per line, and returns what it printed.
+func calculate(t *testing.T, c *Calculator, input string) string {
+	t.Helper()
+	var out bytes.Buffer
+	env := &Env{Out: &out, Log: slog.New(discardHandler{}), Config: defaultConfig}
+	if err := calculatorREPL(context.Background(), &plainEditor{strings.NewReader(input), &out, "> "}, c, env); err != nil {
+		t.Fatalf("calculatorREPL: %v", err)
+	}
+	return out.String()
+}
+
+func TestCalculatorBases(t *testing.T) {
+	out := calculate(t, NewCalculator(), "0xFF & 0x0F\nas hex\n0xFF & 0x0F\n1 << 4\nas bin\n5\nas dec\n0b101\n")
+	checkInOrder(t, out, "15\n", "0xF\n", "0x10\n", "0b101\n", "5\n")
+	if strings.Contains(out, "Error:") {
+		t.Errorf("unexpected error:\n%s", out)
+	}
+}
+
+func TestRoundDecimal(t *testing.T) {
+	tests := []struct {
+		s      string
+		places int
+		want   string
+	}{
+		{"1.005", 2, "1.01"},
+		{"1.004", 2, "1.00"},
+		{"2.5", 0, "3"},
+		{"3.5", 0, "4"},
+		{"-2.5", 0, "-3"},
+		{"9.999", 2, "10.00"},
+		{"9.999", 0, "10"},
+		{"99.95", 1, "100.0"},
+		{"0.125", 2, "0.13"},
+		{"1.5", 3, "1.500"},
+		{"42", 2, "42.00"},
+		{"-0.004", 2, "-0.00"}, // formatResult drops the sign
+	}
+	for _, tt := range tests {
+		if got := roundDecimal(tt.s, tt.places); got != tt.want {
+			t.Errorf("roundDecimal(%q, %d) = %q, want %q", tt.s, tt.places, got, tt.want)
+		}
+	}
+}
+
+func TestFormatResult(t *testing.T) {
+	tests := []struct {
+		precision int
+		group     bool
+		v         float64
+		want      string
+	}{
+		{-1, false, 1234567.5, "1.2345675e+06"},
+		{-1, true, 1234567.5, "1,234,567.5"},
+		{-1, true, -1234567, "-1,234,567"},
+		{-1, true, 123, "123"},
+		{-1, true, 1000, "1,000"},
+		{-1, true, -100000, "-100,000"},
+		{2, false, 1.005, "1.01"},
+		{2, false, -0.001, "0.00"},
+		{0, false, 2.5, "3"},
+		{2, false, 9.999, "10.00"},
+		{2, true, 9999999.999, "10,000,000.00"},
+		{3, true, -1234.5, "-1,234.500"},
+	}
+	for _, tt := range tests {
+		c := NewCalculator()
+		c.precision, c.group = tt.precision, tt.group
+		if got := c.formatResult(tt.v); got != tt.want {
+			t.Errorf("formatResult(%v) with precision %d, group %v = %q, want %q", tt.v, tt.precision, tt.group, got, tt.want)
+		}
+	}
+}
+
+func TestFormatResultSpanish(t *testing.T) {
+	useLocale(t, "es")
+	c := NewCalculator()
+	c.precision, c.group = 2, true
+	if got := c.formatResult(-1234567.891); got != "-1.234.567,89" {
+		t.Errorf("formatResult in Spanish = %q, want -1.234.567,89", got)
+	}
+	out := calculate(t, NewCalculator(), "format group on\n1000000 / 4\n")
+	checkInOrder(t, out, "250.000\n")
+}
+
+func TestCalculatorKeepsGoing(t *testing.T) {
+	// A bad line is reported and the next one is read as if it had not
+	// been there.
+	out := calculate(t, NewCalculator(), "1 + 1\n1 / 0\n2 * 3\n2 +\nfoo(1)\nx = 4\n)\nx * 2\n")
+	checkInOrder(t, out, "2\n", "Error: cannot divide by zero", "6\n", "Error: unexpected end of expression",
+		`Error: unknown function "foo"`, "4\n", `Error: unexpected ")"`, "8\n")
+}
+
+func TestCalculatorSuggestsCommands(t *testing.T) {
+	out := calculate(t, NewCalculator(), "hlp\nhistroy\nhlp + 1\nx\nzzzzz\n")
+	checkInOrder(t, out,
+		`Error: unknown command "hlp", did you mean "help"?`,
+		`Error: unknown command "histroy", did you mean "history"?`,
+		// Only a line that is a single word is taken for a command.
+		"Error: undefined variable: hlp",
+		"Error: undefined variable: x",
+		"Error: undefined variable: zzzzz")
+}
+
+func TestCalculatorAngleMode(t *testing.T) {
+	// A new calculator is in radians. Each mode applies from the next line
+	// on, and the prompt shows it.
+	out := calculate(t, NewCalculator(), "sin(90)\nmode deg\nsin(90)\nacos(0)\nmode rad\nsin(pi/2)\nacos(0)\nmode grad\n")
+	checkInOrder(t, out,
+		"rad> 0.893996663600558\n",
+		"rad> deg> 1\n", "deg> 90\n",
+		"deg> rad> 1\n", "rad> 1.5707963267948966\n",
+		"rad> Error: expected mode big, float, deg or rad")
+}
+
+func TestCalculatorMemory(t *testing.T) {
+	c := NewCalculator()
+	calc := func(expr string) func() error {
+		return func() error {
+			_, _, err := c.Calculate(expr)
+			return err
+		}
+	}
+	memoryClear := func() error { c.MemoryClear(); return nil }
+	steps := []struct {
+		name   string
+		do     func() error
+		err    error
+		memory float64
+		prompt string
+	}{
+		{"MS before any result", c.MemoryStore, errNoResult, 0, "rad> "},
+		{"M+ before any result", c.MemoryAdd, errNoResult, 0, "rad> "},
+		{"M- before any result", c.MemorySubtract, errNoResult, 0, "rad> "},
+		{"a result", calc("5"), nil, 0, "rad> "},
+		{"MS", c.MemoryStore, nil, 5, "M rad> "},
+		{"M+", c.MemoryAdd, nil, 10, "M rad> "},
+		{"another result", calc("3"), nil, 10, "M rad> "},
+		{"M-", c.MemorySubtract, nil, 7, "M rad> "},
+		{"MR * 2", calc("MR * 2"), nil, 7, "M rad> "},
+		{"M+ of MR * 2", c.MemoryAdd, nil, 21, "M rad> "},
+		{"MC", memoryClear, nil, 0, "rad> "},
+		{"M- from zero", c.MemorySubtract, nil, -14, "M rad> "},
+	}
+	for _, s := range steps {
+		if err := s.do(); err != s.err {
+			t.Fatalf("%s: err = %v, want %v", s.name, err, s.err)
+		}
+		if got := c.MemoryRecall(); got != s.memory {
+			t.Errorf("%s: memory = %v, want %v", s.name, got, s.memory)
+		}
+		if got := c.prompt(); got != s.prompt {
+			t.Errorf("%s: prompt = %q, want %q", s.name, got, s.prompt)
+		}
+	}
+	if c.last != 14 {
+		t.Errorf("MR * 2 with 7 in memory = %v, want 14", c.last)
+	}
+}
+
+func TestCalculatorContinues(t *testing.T) {
+	useHistory(t, nil)
+	out := calculate(t, NewCalculator(), "+ 20\n10\n+ 20\n* 2\n- 5\n-5\n^ 2\n+ 2 )\n% 3\nxor 1\n")
+	checkInOrder(t, out,
+		"Error: no previous result to continue from",
+		"10\n", "30\n", "60\n", "55\n", "-5\n", "25\n",
+		// Positions are in the line as typed, without the ans before it.
+		"Error: unmatched ')' at position 5",
+		`Error: unexpected "%" at position 1`,
+		"24\n")
+	h := GetHistory()
+	if got := h[len(h)-1].Expr; got != "ans xor 1" {
+		t.Errorf("the history shows the continuation as %q, want it from ans", got)
+	}
+}
+
+func TestContinuesResult(t *testing.T) {
+	for line, want := range map[string]bool{
+		"+ 20": true, "* 2": true, "- 5": true, "/ 4": true, "^2": true, "xor 1": true,
+		"<< 1": true, "& 3": true, "| 8": true,
+		"-5": false, "% 3": false, "15%": false, "2 + 3": false, "x = 1": false, "": false,
+	} {
+		if got := continuesResult(line); got != want {
+			t.Errorf("continuesResult(%q) = %v, want %v", line, got, want)
+		}
+	}
+}
+
+func TestCalculatorUndo(t *testing.T) {
+	useHistory(t, nil)
+	c := NewCalculator()
+	out := calculate(t, c, "x = 1\nx = 2\nundo\nvars\nredo\nvars\nMS\nundo\nvars\nredo\n")
+	checkInOrder(t, out,
+		"rad> 1\n", "rad> 2\n",
+		"rad> rad> x = 1\n",        // undo x = 2
+		"rad> rad> x = 2\n",        // redo it
+		"rad> M rad> rad> x = 2\n", // MS and its undo leave x alone
+		"rad> M rad> ")             // redo MS
+	if c.memory != 2 {
+		t.Errorf("memory after redoing MS = %v, want 2", c.memory)
+	}
+
+	// A new change after an undo cannot be followed by a redo of the
+	// change undone.
+	out = calculate(t, c, "undo\nundo\nx = 5\nredo\nvars\nundo\nvars\nundo\nvars\nundo\n")
+	checkInOrder(t, out,
+		"rad> 5\n", "rad> Error: nothing to redo\n", "rad> x = 5\n",
+		"rad> rad> x = 1\n",             // undo x = 5
+		"rad> rad> No variables yet.\n", // undo x = 1
+		"rad> Error: nothing to undo\n")
+}
@@ -0,0 +1,16 @@
+package main
+
+import "time"
+
+// clock is the source of time for code that needs to be tested without
+// really waiting.
+type clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+// realClock is the wall clock.
+type realClock struct{}
+
+func (realClock) Now() time.Time        { return time.Now() }
+func (realClock) Sleep(d time.Duration) { time.Sleep(d) }
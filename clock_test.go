@@ -0,0 +1,24 @@
+package main
+
+import "time"
+
+// fakeClock is a clock for tests. Time only moves when Sleep or Advance is
+// called, and Sleep returns at once.
+type fakeClock struct {
+	now   time.Time
+	slept []time.Duration // every duration passed to Sleep
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.slept = append(c.slept, d)
+	c.now = c.now.Add(d)
+}
+
+// Advance moves the clock on by d without anyone sleeping.
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Theme holds the colors the interface is drawn in, as ANSI SGR codes
+// such as "1;36". An empty code leaves that kind of text plain.
+type Theme struct {
+	Title  string // menu titles
+	Number string // menu numbers such as [1]
+	Prompt string // app prompts such as "todo> "
+	Error  string // the "Error:" before error messages
+}
+
+// themes are the built-in themes DEMO_THEME can name.
+var themes = map[string]Theme{
+	"dark":  {Title: "1;36", Number: "32", Prompt: "33", Error: "1;31"},
+	"light": {Title: "1;34", Number: "35", Prompt: "34", Error: "31"},
+	"mono":  {},
+}
+
+// defaultTheme is used when DEMO_THEME is unset or names no theme.
+const defaultTheme = "dark"
+
+// theme is the theme resolved at startup by setupTheme. Rendering code is
+// passed it; only printError, which is called from everywhere, reads it
+// here.
+var theme = themes["mono"]
+
+// setupTheme resolves the theme named by DEMO_THEME, warning on stderr and
+// using the default when it is unknown. Output gets the mono theme unless
+// w is a terminal, NO_COLOR is unset and the config allows colors.
+func setupTheme(w io.Writer, allowed bool) Theme {
+	name := os.Getenv("DEMO_THEME")
+	if name == "" {
+		name = defaultTheme
+	}
+	t, ok := themes[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "%s "+T("theme.unknown")+"\n",
+			T("warning"), name, strings.Join(themeNames(), ", "), defaultTheme)
+		t = themes[defaultTheme]
+	}
+	if !allowed || !useColor(w) {
+		t = themes["mono"]
+	}
+	theme = t
+	return t
+}
+
+// themeNames returns the names of the built-in themes in order.
+func themeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func useColor(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// paint wraps s in the ANSI escape for code, or returns s unchanged when
+// code is empty.
+func paint(s, code string) string {
+	if code == "" {
+		return s
+	}
+	return "\033[" + code + "m" + s + "\033[0m"
+}
+
+func (t Theme) title(s string) string  { return paint(s, t.Title) }
+func (t Theme) number(s string) string { return paint(s, t.Number) }
+func (t Theme) prompt(s string) string { return paint(s, t.Prompt) }
+
+// errorHook, if set, is called with every error printError shows. Script
+// mode uses it to stop at the first error.
+var errorHook func(error)
+
+// printError writes err to w after an "Error:" in the theme's color.
+func printError(w io.Writer, err error) {
+	fmt.Fprintln(w, paint(T("error"), theme.Error), err)
+	if errorHook != nil {
+		errorHook(err)
+	}
+}
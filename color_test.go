@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	t.Setenv("DEMO_THEME", "dark")
+	saved := theme
+	t.Cleanup(func() { theme = saved })
+
+	if got := setupTheme(os.Stdout, true); got != themes["mono"] {
+		t.Errorf("setupTheme with NO_COLOR = %+v, want the mono theme", got)
+	}
+	out := runMenu(t, defaultConfig, menuInput("{Exit}0\n{Exit}\n"))
+	var errOut bytes.Buffer
+	printError(&errOut, errors.New("broken"))
+	for _, s := range []string{out, errOut.String()} {
+		if strings.Contains(s, "\033[") {
+			t.Errorf("output with NO_COLOR has escape sequences: %q", s)
+		}
+	}
+}
+
+func TestThemes(t *testing.T) {
+	saved := theme
+	t.Cleanup(func() { theme = saved })
+
+	theme = themes["mono"]
+	mono := runMenu(t, defaultConfig, menuInput("{Exit}0\n{Exit}\n"))
+	if strings.Contains(mono, "\033") {
+		t.Errorf("the mono theme has escape sequences: %q", mono)
+	}
+	checkInOrder(t, mono, "Choose app:\n[1] Tools >", "Error: please enter")
+
+	theme = themes["dark"]
+	dark := runMenu(t, defaultConfig, menuInput("{Exit}0\n{Exit}\n"))
+	checkInOrder(t, dark, "\033[1;36mChoose app:\033[0m\n", "\033[32m[1]\033[0m Tools >", "\033[1;31mError:\033[0m please enter")
+
+	dt := themes["dark"]
+	if got := dt.prompt("todo> "); got != "\033[33mtodo> \033[0m" {
+		t.Errorf("dark prompt = %q", got)
+	}
+	if got := themes["light"].title("Main"); got != "\033[1;34mMain\033[0m" {
+		t.Errorf("light title = %q", got)
+	}
+}
+
+func TestSetupTheme(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	saved := theme
+	t.Cleanup(func() { theme = saved })
+	// Output that is not a terminal, or colors turned off in the config,
+	// always get mono.
+	for _, name := range []string{"dark", "light", ""} {
+		t.Setenv("DEMO_THEME", name)
+		if got := setupTheme(&bytes.Buffer{}, true); got != themes["mono"] {
+			t.Errorf("setupTheme(buffer) with DEMO_THEME=%q = %+v, want mono", name, got)
+		}
+		if got := setupTheme(os.Stdout, false); got != themes["mono"] {
+			t.Errorf("setupTheme with colors off and DEMO_THEME=%q = %+v, want mono", name, got)
+		}
+	}
+	if got := themeNames(); strings.Join(got, " ") != "dark light mono" {
+		t.Errorf("themeNames() = %q", got)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+)
+
+// Config holds the settings read from ~/.demo-go/config.json. Every field
+// is optional; see defaultConfig for what a missing one means.
+type Config struct {
+	Title           string   `json:"title,omitempty"`    // shown above the menu; "" for the translated default
+	Disabled        []string `json:"disabled,omitempty"` // IDs of apps left out of the menu
+	CalculatorMode  string   `json:"calculator_mode"`    // "float" or "big"
+	Color           bool     `json:"color"`              // false turns colors off everywhere
+	InputTimeout    int      `json:"input_timeout"`      // seconds without input before an app gives up; 0 for never
+	RememberLastApp bool     `json:"remember_last_app"`  // true saves LastApp here whenever an app is launched
+	LastApp         string   `json:"last_app,omitempty"` // ID of the app Enter launches on the main menu; ignored if unknown or disabled
+}
+
+// defaultConfig is the configuration used when there is no config file.
+var defaultConfig = Config{
+	CalculatorMode: "float",
+	Color:          true,
+}
+
+// LoadConfig reads the config file at path on top of the defaults. A
+// missing file gives the defaults; a field that is misspelled or has a bad
+// value is an error.
+func LoadConfig(path string) (Config, error) {
+	c := defaultConfig
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return Config{}, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&c); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := c.validate(); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// SaveConfig writes c to path as indented JSON, replacing the file
+// atomically.
+func SaveConfig(path string, c Config) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+func (c Config) validate() error {
+	for _, id := range c.Disabled {
+		if _, ok := findApp(id); !ok {
+			return newError("config.unknown_app", id)
+		}
+	}
+	if c.CalculatorMode != "float" && c.CalculatorMode != "big" {
+		return newError("config.calculator_mode", c.CalculatorMode)
+	}
+	if c.InputTimeout < 0 {
+		return newError("config.input_timeout", c.InputTimeout)
+	}
+	return nil
+}
+
+// enabled reports whether the app with the given ID is in the menu.
+func (c Config) enabled(id string) bool {
+	return !slices.Contains(c.Disabled, id)
+}
+
+// enabledApps returns the registered apps that are enabled, in menu order.
+func (c Config) enabledApps() []App {
+	var menu []App
+	for _, app := range apps {
+		if c.enabled(app.ID) {
+			menu = append(menu, app)
+		}
+	}
+	return menu
+}
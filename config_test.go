@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeConfig writes data to a config file in a new directory and returns
+// its path.
+func writeConfig(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	c, err := LoadConfig(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil || !reflect.DeepEqual(c, defaultConfig) {
+		t.Errorf("LoadConfig of a missing file = %+v, %v; want the defaults", c, err)
+	}
+
+	// Fields left out keep their defaults.
+	c, err = LoadConfig(writeConfig(t, `{"title": "Toys", "disabled": ["dice", "guess"], "input_timeout": 30}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := defaultConfig
+	want.Title, want.Disabled, want.InputTimeout = "Toys", []string{"dice", "guess"}, 30
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("LoadConfig = %+v, want %+v", c, want)
+	}
+	if c.enabled("dice") || !c.enabled("calculator") {
+		t.Errorf("enabled() does not follow disabled: %v", c.Disabled)
+	}
+
+	c, err = LoadConfig(writeConfig(t, `{"calculator_mode": "big", "color": false, "remember_last_app": true, "last_app": "todo"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.CalculatorMode != "big" || c.Color || !c.RememberLastApp || c.LastApp != "todo" {
+		t.Errorf("LoadConfig = %+v", c)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		name, data, want string
+	}{
+		{"unknown field", `{"colour": false}`, `unknown field "colour"`},
+		{"bad disabled app", `{"disabled": ["calculator", "chess"]}`, `disabled: unknown app "chess"`},
+		{"bad calculator_mode", `{"calculator_mode": "exact"}`, `calculator_mode: want "float" or "big", not "exact"`},
+		{"negative input_timeout", `{"input_timeout": -1}`, "input_timeout: want a number of seconds >= 0, not -1"},
+		{"wrong type", `{"color": "no"}`, "cannot unmarshal string"},
+		{"not JSON", `{"title": `, "unexpected EOF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.data)
+			_, err := LoadConfig(path)
+			if err == nil {
+				t.Fatalf("LoadConfig(%s) succeeded", tt.data)
+			}
+			if !strings.HasPrefix(err.Error(), path+": ") || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadConfig(%s) error = %q, want the path and %q", tt.data, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveConfigRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	want := defaultConfig
+	want.Disabled, want.LastApp = []string{"stats"}, "encode"
+	if err := SaveConfig(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadConfig(path)
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadConfig after SaveConfig = %+v, %v; want %+v", got, err, want)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	Register(App{
+		ID:          "convert",
+		Name:        "Unit Converter",
+		Description: "convert lengths, weights and temperatures",
+		Usage:       convertHelp,
+		Group:       "Tools",
+		Run:         convertUnits,
+	})
+}
+
+// convertHelp is the converter's usage, printed by "help" at any prompt.
+const convertHelp = `Enter a value, then the unit it is in, then the unit to convert to.
+Units: m ft mi (length), kg lb (weight), C F K (temperature)
+Type q at any prompt to go back to the menu.`
+
+// unit converts to and from the base unit of its dimension: metres,
+// kilograms or kelvin. Functions rather than factors are needed because
+// temperature scales are offset from each other, not just scaled.
+type unit struct {
+	dimension string
+	toBase    func(float64) float64
+	fromBase  func(float64) float64
+}
+
+// scaled returns a unit that is factor base units.
+func scaled(dimension string, factor float64) unit {
+	return unit{
+		dimension: dimension,
+		toBase:    func(v float64) float64 { return v * factor },
+		fromBase:  func(v float64) float64 { return v / factor },
+	}
+}
+
+// units are keyed by lower case symbol.
+var units = map[string]unit{
+	"m":  scaled("length", 1),
+	"ft": scaled("length", 0.3048),
+	"mi": scaled("length", 1609.344),
+	"kg": scaled("weight", 1),
+	"lb": scaled("weight", 0.45359237),
+	"k":  scaled("temperature", 1),
+	"c": {
+		dimension: "temperature",
+		toBase:    func(v float64) float64 { return v + 273.15 },
+		fromBase:  func(v float64) float64 { return v - 273.15 },
+	},
+	"f": {
+		dimension: "temperature",
+		toBase:    func(v float64) float64 { return (v-32)*5/9 + 273.15 },
+		fromBase:  func(v float64) float64 { return (v-273.15)*9/5 + 32 },
+	},
+}
+
+// convert converts value between two unit symbols of the same dimension.
+func convert(value float64, from, to string) (float64, error) {
+	f, ok := units[strings.ToLower(from)]
+	if !ok {
+		return 0, newError("convert.unknown_unit", from)
+	}
+	t, ok := units[strings.ToLower(to)]
+	if !ok {
+		return 0, newError("convert.unknown_unit", to)
+	}
+	if f.dimension != t.dimension {
+		return 0, newError("convert.incompatible", from, T("dimension."+f.dimension), to, T("dimension."+t.dimension))
+	}
+	return t.fromBase(f.toBase(value)), nil
+}
+
+// formatConverted rounds v to four decimal places and drops trailing zeros.
+func formatConverted(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1e4)/1e4, 'f', -1, 64)
+}
+
+// convertUnits asks for a value and two units and prints the conversion,
+// until the user types "q" or the input ends.
+func convertUnits(_ context.Context, env *Env) error {
+	r, w := env.In, env.Out
+	fmt.Fprintln(w, T("convert.units"))
+	prompts := []string{T("convert.value"), T("convert.from"), T("convert.to")}
+	for {
+		answers := make([]string, 0, len(prompts))
+		for len(answers) < len(prompts) {
+			fmt.Fprintln(w, prompts[len(answers)])
+			line, err := readLine(r)
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			line = strings.TrimSpace(line)
+			if line == "q" {
+				return nil
+			}
+			if line == "help" {
+				fmt.Fprintln(w, translate("app.convert.usage", convertHelp))
+				continue
+			}
+			answers = append(answers, line)
+		}
+
+		value, err := strconv.ParseFloat(answers[0], 64)
+		if err != nil {
+			printError(w, newError("input.bad_number", answers[0]))
+			continue
+		}
+		result, err := convert(value, answers[1], answers[2])
+		if err != nil {
+			printError(w, err)
+			continue
+		}
+		env.Log.Info("converted", "value", value, "from", answers[1], "to", answers[2], "result", result)
+		fmt.Fprintf(w, "%s %s = %s %s\n", answers[0], answers[1], formatConverted(result), answers[2])
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		value    float64
+		from, to string
+		want     float64
+	}{
+		{100, "C", "F", 212},
+		{212, "F", "K", 373.15},
+		{373.15, "K", "C", 100},
+		{100, "C", "K", 373.15},
+		{212, "F", "C", 100},
+		{373.15, "K", "F", 212},
+		{-40, "C", "F", -40},
+		{-40, "F", "C", -40},
+		{0, "K", "C", -273.15},
+		{32, "f", "c", 0},
+		{1, "ft", "m", 0.3048},
+		{1, "m", "ft", 1 / 0.3048},
+		{1, "mi", "ft", 5280},
+		{1, "mi", "m", 1609.344},
+		{1, "lb", "kg", 0.45359237},
+		{1, "kg", "lb", 1 / 0.45359237},
+		{5, "KG", "Kg", 5},
+	}
+	for _, tt := range tests {
+		got, err := convert(tt.value, tt.from, tt.to)
+		if err != nil {
+			t.Errorf("convert(%v, %s, %s) failed: %v", tt.value, tt.from, tt.to, err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("convert(%v, %s, %s) = %v, want %v", tt.value, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     string
+	}{
+		{"kg", "ft", "cannot convert kg (weight) to ft (length)"},
+		{"C", "m", "cannot convert C (temperature) to m (length)"},
+		{"lb", "F", "cannot convert lb (weight) to F (temperature)"},
+		{"yd", "m", `unknown unit "yd"`},
+		{"m", "furlong", `unknown unit "furlong"`},
+		{"", "m", `unknown unit ""`},
+	}
+	for _, tt := range tests {
+		_, err := convert(1, tt.from, tt.to)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("convert(1, %q, %q) error = %v, want %q", tt.from, tt.to, err, tt.want)
+		}
+	}
+}
+
+func TestFormatConverted(t *testing.T) {
+	tests := []struct {
+		v    float64
+		want string
+	}{
+		{212, "212"},
+		{373.15, "373.15"},
+		{1 / 0.3048, "3.2808"},
+		{2.20462262, "2.2046"},
+		{0.00004, "0"},
+		{0.00005, "0.0001"},
+		{-273.15, "-273.15"},
+		{5280.00000001, "5280"},
+	}
+	for _, tt := range tests {
+		if got := formatConverted(tt.v); got != tt.want {
+			t.Errorf("formatConverted(%v) = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestConvertUnits(t *testing.T) {
+	var out bytes.Buffer
+	env := &Env{In: strings.NewReader("100\nC\nF\nx\nm\nft\n1\nkg\nft\n1\nmi\nft\nq\n"), Out: &out, Log: slog.New(discardHandler{})}
+	if err := convertUnits(context.Background(), env); err != nil {
+		t.Fatal(err)
+	}
+	checkInOrder(t, out.String(), "100 C = 212 F\n", `Error: invalid number "x"`,
+		"Error: cannot convert kg (weight) to ft (length)", "1 mi = 5280 ft\n")
+}
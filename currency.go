@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+func init() {
+	Register(App{
+		ID:          "currency",
+		Name:        "Currency Converter",
+		Description: "convert between USD, EUR, GBP and JPY at live rates",
+		Usage:       currencyHelp,
+		Group:       "Tools",
+		Run:         runCurrency,
+	})
+}
+
+// currencyHelp is the currency converter's usage, printed by "help" at any
+// prompt.
+const currencyHelp = `Enter an amount, then the currency it is in, then the currency to convert to.
+Currencies: USD EUR GBP JPY
+Rates come from api.frankfurter.app and are kept for 10 minutes. If they
+cannot be fetched, built-in rates are used instead.
+Type q at any prompt to go back to the menu.`
+
+// currencies are the currencies the converter knows.
+var currencies = []string{"USD", "EUR", "GBP", "JPY"}
+
+const (
+	rateTimeout    = 5 * time.Second  // how long to wait for the rates API
+	ratesMaxAge    = 10 * time.Minute // how long fetched rates are used
+	frankfurterURL = "https://api.frankfurter.app/latest?from=USD&to=EUR,GBP,JPY"
+)
+
+// RateProvider gives exchange rates as units of each currency per US dollar.
+type RateProvider interface {
+	Rates(ctx context.Context) (map[string]float64, error)
+}
+
+// staticRates is a fixed table of rates. It is the fallback when live rates
+// cannot be fetched, and a stand-in provider that needs no network.
+type staticRates map[string]float64
+
+func (s staticRates) Rates(context.Context) (map[string]float64, error) {
+	return s, nil
+}
+
+// builtinRates are approximate rates to use when offline.
+var builtinRates = staticRates{"USD": 1, "EUR": 0.92, "GBP": 0.79, "JPY": 150}
+
+// httpRates fetches rates from the Frankfurter API at url.
+type httpRates struct {
+	client *http.Client
+	url    string
+}
+
+func (h httpRates) Rates(ctx context.Context) (map[string]float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s", T("currency.fetching"), resp.Status)
+	}
+
+	var body struct {
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("%s %w", T("currency.fetching"), err)
+	}
+	rates := map[string]float64{"USD": 1}
+	for _, c := range currencies[1:] {
+		r, ok := body.Rates[c]
+		if !ok || r <= 0 {
+			return nil, fmt.Errorf("%s %w", T("currency.fetching"), newError("currency.no_rate", c))
+		}
+		rates[c] = r
+	}
+	return rates, nil
+}
+
+// cachedRates remembers the rates from another provider for maxAge.
+type cachedRates struct {
+	provider RateProvider
+	clock    clock
+	maxAge   time.Duration
+
+	mu      sync.Mutex
+	rates   map[string]float64
+	fetched time.Time
+}
+
+func (c *cachedRates) Rates(ctx context.Context) (map[string]float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rates != nil && c.clock.Now().Sub(c.fetched) < c.maxAge {
+		return c.rates, nil
+	}
+	rates, err := c.provider.Rates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.rates, c.fetched = rates, c.clock.Now()
+	return rates, nil
+}
+
+// liveRates is shared by every run of the app so the cache outlives it.
+var liveRates RateProvider = &cachedRates{
+	provider: httpRates{client: http.DefaultClient, url: frankfurterURL},
+	clock:    realClock{},
+	maxAge:   ratesMaxAge,
+}
+
+// convertCurrency converts amount from one currency to another at rates.
+func convertCurrency(amount float64, from, to string, rates map[string]float64) (float64, error) {
+	fromRate, ok := rates[from]
+	if !ok {
+		return 0, newError("currency.unknown", from)
+	}
+	toRate, ok := rates[to]
+	if !ok {
+		return 0, newError("currency.unknown", to)
+	}
+	return amount / fromRate * toRate, nil
+}
+
+// runCurrency asks for an amount and two currencies and prints the
+// conversion, until the user types "q" or the input ends. Once live rates
+// have failed it keeps to the built-in ones rather than waiting on the
+// network for every conversion.
+func runCurrency(ctx context.Context, env *Env) error {
+	r, w := env.In, env.Out
+	fmt.Fprintln(w, T("currency.list"), strings.Join(currencies, " "))
+	var provider RateProvider = liveRates
+	prompts := []string{T("currency.amount"), T("currency.from"), T("currency.to")}
+	for {
+		answers := make([]string, 0, len(prompts))
+		for len(answers) < len(prompts) {
+			fmt.Fprintln(w, prompts[len(answers)])
+			line, err := readLine(r)
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			line = strings.TrimSpace(line)
+			if line == "q" {
+				return nil
+			}
+			if line == "help" {
+				fmt.Fprintln(w, translate("app.currency.usage", currencyHelp))
+				continue
+			}
+			answers = append(answers, line)
+		}
+
+		amount, err := strconv.ParseFloat(answers[0], 64)
+		if err != nil {
+			printError(w, newError("input.bad_number", answers[0]))
+			continue
+		}
+		fetch, cancel := context.WithTimeout(ctx, rateTimeout)
+		rates, err := provider.Rates(fetch)
+		cancel()
+		if ctx.Err() != nil {
+			return ctx.Err() // Ctrl+C rather than a slow or failed fetch
+		}
+		if err != nil {
+			fmt.Fprintln(w, T("warning"), T("currency.builtin"), err)
+			provider = builtinRates
+			rates = builtinRates
+		}
+		from, to := strings.ToUpper(answers[1]), strings.ToUpper(answers[2])
+		result, err := convertCurrency(amount, from, to, rates)
+		if err != nil {
+			printError(w, err)
+			continue
+		}
+		env.Log.Info("converted", "amount", amount, "from", from, "to", to, "result", result)
+		fmt.Fprintf(w, "%s %s = %.2f %s\n", answers[0], from, result, to)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeRates is a RateProvider that counts its calls and returns rates, or
+// err if it is set.
+type fakeRates struct {
+	rates map[string]float64
+	err   error
+	calls int
+}
+
+func (f *fakeRates) Rates(context.Context) (map[string]float64, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.rates, nil
+}
+
+func TestCachedRates(t *testing.T) {
+	fake := &fakeRates{rates: map[string]float64{"USD": 1, "EUR": 0.5}}
+	c := newFakeClock()
+	cache := &cachedRates{provider: fake, clock: c, maxAge: 10 * time.Minute}
+	ctx := context.Background()
+
+	for range 3 {
+		if rates, err := cache.Rates(ctx); err != nil || rates["EUR"] != 0.5 {
+			t.Fatalf("Rates() = %v, %v", rates, err)
+		}
+	}
+	if fake.calls != 1 {
+		t.Errorf("fetched %d times within maxAge, want 1", fake.calls)
+	}
+
+	c.Advance(9 * time.Minute)
+	cache.Rates(ctx)
+	if fake.calls != 1 {
+		t.Errorf("fetched again before maxAge")
+	}
+	c.Advance(time.Minute)
+	fake.rates = map[string]float64{"USD": 1, "EUR": 0.6}
+	if rates, _ := cache.Rates(ctx); rates["EUR"] != 0.6 || fake.calls != 2 {
+		t.Errorf("after maxAge: rates %v after %d fetches, want new rates after 2", rates, fake.calls)
+	}
+
+	// A failed fetch is not cached, and does not keep the stale rates.
+	c.Advance(time.Hour)
+	fake.err = errors.New("offline")
+	if _, err := cache.Rates(ctx); err != fake.err {
+		t.Errorf("Rates() error = %v, want the provider's", err)
+	}
+	fake.err = nil
+	if _, err := cache.Rates(ctx); err != nil || fake.calls != 4 {
+		t.Errorf("after a failure: err %v after %d fetches, want a new fetch", err, fake.calls)
+	}
+}
+
+func TestCurrencyFallsBack(t *testing.T) {
+	fake := &fakeRates{err: errors.New("offline")}
+	saved := liveRates
+	liveRates = fake
+	t.Cleanup(func() { liveRates = saved })
+
+	var out bytes.Buffer
+	env := &Env{
+		In:  strings.NewReader("100\nusd\neur\n10\nGBP\nUSD\nq\n"),
+		Out: &out,
+		Log: slog.New(discardHandler{}),
+	}
+	if err := runCurrency(context.Background(), env); err != nil {
+		t.Fatal(err)
+	}
+	checkInOrder(t, out.String(),
+		"Warning: using built-in rates, live rates are unavailable: offline",
+		"100 USD = 92.00 EUR",
+		"10 GBP = 12.66 USD",
+	)
+	if n := strings.Count(out.String(), "Warning:"); n != 1 {
+		t.Errorf("warned %d times, want once", n)
+	}
+	if fake.calls != 1 {
+		t.Errorf("tried live rates %d times, want once", fake.calls)
+	}
+}
+
+func TestConvertCurrency(t *testing.T) {
+	if got, err := convertCurrency(150, "JPY", "EUR", builtinRates); err != nil || got != 0.92 {
+		t.Errorf("150 JPY = %v EUR, %v; want 0.92", got, err)
+	}
+	if _, err := convertCurrency(1, "USD", "CHF", builtinRates); err == nil || err.Error() != `unknown currency "CHF"` {
+		t.Errorf("converting to CHF: err = %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register(App{
+		ID:          "dice",
+		Name:        "Dice Roller",
+		Description: "roll dice written like 3d6+2",
+		Usage:       diceHelp,
+		Run:         runDice,
+	})
+}
+
+// Limits that keep a roll short enough to read.
+const (
+	maxDice     = 100
+	maxSides    = 1000
+	maxModifier = 1_000_000
+)
+
+// diceHelp is the app's usage, printed by its "help" command.
+var diceHelp = fmt.Sprintf(`Type dice to roll as NdM+K: N dice with M sides each, plus K.
+N and K are optional, so d20 rolls one die and 2d6-1 takes 1 off the total.
+N can be up to %d and M up to %d. Type q to go back to the menu.`, maxDice, maxSides)
+
+// Dice is a roll in dice notation: Count dice with Sides sides each, with
+// Modifier added to the total.
+type Dice struct {
+	Count    int
+	Sides    int
+	Modifier int
+}
+
+func (d Dice) String() string {
+	s := fmt.Sprintf("%dd%d", d.Count, d.Sides)
+	if d.Modifier != 0 {
+		s += fmt.Sprintf("%+d", d.Modifier)
+	}
+	return s
+}
+
+// parseDice reads dice notation such as "3d6+2", "1d20" or "d8-1".
+func parseDice(s string) (Dice, error) {
+	bad := newError("dice.bad", s)
+	count, rest, ok := strings.Cut(strings.ToLower(strings.TrimSpace(s)), "d")
+	if !ok {
+		return Dice{}, bad
+	}
+	sides, modifier := rest, ""
+	if i := strings.IndexAny(rest, "+-"); i >= 0 {
+		sides, modifier = rest[:i], rest[i:]
+	}
+
+	d := Dice{Count: 1}
+	var err error
+	if count != "" {
+		if d.Count, err = diceNumber(count); err != nil {
+			return Dice{}, bad
+		}
+	}
+	if d.Sides, err = diceNumber(sides); err != nil {
+		return Dice{}, bad
+	}
+	if modifier != "" {
+		// The sign is there, so the digits after it must be too.
+		k, err := diceNumber(modifier[1:])
+		if err != nil {
+			return Dice{}, bad
+		}
+		d.Modifier = k
+		if modifier[0] == '-' {
+			d.Modifier = -k
+		}
+	}
+
+	switch {
+	case d.Count < 1 || d.Count > maxDice:
+		return Dice{}, newError("dice.count", maxDice, d.Count)
+	case d.Sides < 1 || d.Sides > maxSides:
+		return Dice{}, newError("dice.sides", maxSides, d.Sides)
+	case d.Modifier < -maxModifier || d.Modifier > maxModifier:
+		return Dice{}, newError("dice.modifier", -maxModifier, maxModifier)
+	}
+	return d, nil
+}
+
+// diceNumber parses the digits of one part of dice notation. Signs and
+// spaces are not digits, so "3d+6" and "3d 6" are errors.
+func diceNumber(s string) (int, error) {
+	if s == "" || strings.Trim(s, "0123456789") != "" {
+		return 0, strconv.ErrSyntax
+	}
+	return strconv.Atoi(s)
+}
+
+// Roll rolls d with rng and returns each die and the total with the
+// modifier added.
+func (d Dice) Roll(rng *rand.Rand) (rolls []int, total int) {
+	rolls = make([]int, d.Count)
+	for i := range rolls {
+		rolls[i] = rng.Intn(d.Sides) + 1
+		total += rolls[i]
+	}
+	return rolls, total + d.Modifier
+}
+
+// formatRoll shows a roll of d like "3d6+2: 4 1 6 +2 = 13".
+func formatRoll(d Dice, rolls []int, total int) string {
+	words := make([]string, len(rolls))
+	for i, r := range rolls {
+		words[i] = strconv.Itoa(r)
+	}
+	s := d.String() + ": " + strings.Join(words, " ")
+	if d.Modifier != 0 {
+		s += fmt.Sprintf(" %+d", d.Modifier)
+	}
+	return fmt.Sprintf("%s = %d", s, total)
+}
+
+// runDice rolls dice with a time-based seed.
+func runDice(_ context.Context, env *Env) error {
+	return rollDice(env, rand.New(rand.NewSource(time.Now().UnixNano())))
+}
+
+// rollDice reads dice notation and prints a roll of it with rng, until the
+// user types "q".
+func rollDice(env *Env, rng *rand.Rand) error {
+	r, w := env.In, env.Out
+	fmt.Fprintln(w, T("dice.welcome"))
+	for {
+		fmt.Fprint(w, env.Theme.prompt(T("dice.prompt")))
+		line, err := readLine(r)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		switch line {
+		case "":
+			continue
+		case "q":
+			return nil
+		case "help":
+			fmt.Fprintln(w, translate("app.dice.usage", diceHelp))
+			continue
+		}
+		d, err := parseDice(line)
+		if err != nil {
+			printError(w, err)
+			continue
+		}
+		rolls, total := d.Roll(rng)
+		env.Log.Info("rolled", "dice", d.String(), "total", total)
+		fmt.Fprintln(w, formatRoll(d, rolls, total))
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseDice(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Dice
+	}{
+		{"3d6+2", Dice{3, 6, 2}},
+		{"1d20", Dice{1, 20, 0}},
+		{"d8-1", Dice{1, 8, -1}},
+		{" 2D10+0 ", Dice{2, 10, 0}},
+		{"100d1000+1000000", Dice{maxDice, maxSides, maxModifier}},
+	}
+	for _, tt := range tests {
+		got, err := parseDice(tt.s)
+		if err != nil || got != tt.want {
+			t.Errorf("parseDice(%q) = %+v, %v; want %+v", tt.s, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseDiceErrors(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"", `"" is not dice notation like 3d6+2`},
+		{"6", `"6" is not dice notation like 3d6+2`},
+		{"3d", `"3d" is not dice notation like 3d6+2`},
+		{"3d+6", `"3d+6" is not dice notation like 3d6+2`},
+		{"3d6+", `"3d6+" is not dice notation like 3d6+2`},
+		{"3d 6", `"3d 6" is not dice notation like 3d6+2`},
+		{"-3d6", `"-3d6" is not dice notation like 3d6+2`},
+		{"3d6+2+1", `"3d6+2+1" is not dice notation like 3d6+2`},
+		{"xd6", `"xd6" is not dice notation like 3d6+2`},
+		{"0d6", "the number of dice must be from 1 to 100, not 0"},
+		{"101d6", "the number of dice must be from 1 to 100, not 101"},
+		{"3d0", "the number of sides must be from 1 to 1000, not 0"},
+		{"3d1001", "the number of sides must be from 1 to 1000, not 1001"},
+		{"3d6+1000001", "the number added must be from -1000000 to 1000000"},
+		{"3d6-1000001", "the number added must be from -1000000 to 1000000"},
+	}
+	for _, tt := range tests {
+		_, err := parseDice(tt.s)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("parseDice(%q) error = %v, want %q", tt.s, err, tt.want)
+		}
+	}
+}
+
+func TestDiceRoll(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tests := []struct {
+		d     Dice
+		rolls []int
+		total int
+		shown string
+	}{
+		{Dice{3, 6, 2}, []int{6, 4, 6}, 18, "3d6+2: 6 4 6 +2 = 18"},
+		{Dice{1, 20, 0}, []int{20}, 20, "1d20: 20 = 20"},
+		{Dice{2, 6, -1}, []int{2, 1}, 2, "2d6-1: 2 1 -1 = 2"},
+	}
+	for _, tt := range tests {
+		rolls, total := tt.d.Roll(rng)
+		if !slices.Equal(rolls, tt.rolls) || total != tt.total {
+			t.Errorf("%v rolled %v = %d, want %v = %d", tt.d, rolls, total, tt.rolls, tt.total)
+		}
+		if got := formatRoll(tt.d, rolls, total); got != tt.shown {
+			t.Errorf("formatRoll = %q, want %q", got, tt.shown)
+		}
+	}
+
+	// Every die lands on one of its sides.
+	d := Dice{maxDice, 6, 0}
+	rolls, _ := d.Roll(rng)
+	for _, r := range rolls {
+		if r < 1 || r > 6 {
+			t.Fatalf("a d6 rolled %d", r)
+		}
+	}
+}
+
+func TestRollDice(t *testing.T) {
+	var out bytes.Buffer
+	env := &Env{In: strings.NewReader("3d6+2\n\n7\n1d20\nq\n"), Out: &out, Log: slog.New(discardHandler{})}
+	if err := rollDice(env, rand.New(rand.NewSource(1))); err != nil {
+		t.Fatal(err)
+	}
+	checkInOrder(t, out.String(), "3d6+2: 6 4 6 +2 = 18\n", `Error: "7" is not dice notation`, "1d20: 20 = 20\n")
+}
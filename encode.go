@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+func init() {
+	Register(App{
+		ID:          "encode",
+		Name:        "Encoder",
+		Description: "encode and decode text as base64 or hex",
+		Usage:       encodeHelp,
+		Run:         runEncode,
+	})
+}
+
+// encodeHelp is the encoder's usage, printed by its "help" command.
+const encodeHelp = `Commands:
+  b64e <text>   encode text as base64
+  b64d <text>   decode base64
+  hexe <text>   encode text as hex
+  hexd <text>   decode hex
+  help          show this text
+  q             back to the menu
+Everything after the first space is the text, spaces included.`
+
+// encodeCommands are the codecs and other commands runEncode knows.
+var encodeCommands = []string{"b64e", "b64d", "hexe", "hexd", "help", "q"}
+
+var errInvalidEncoding = newError("encode.invalid")
+
+// codecs maps each command to the function it applies to its text.
+var codecs = map[string]func(string) (string, error){
+	"b64e": func(s string) (string, error) { return base64.StdEncoding.EncodeToString([]byte(s)), nil },
+	"b64d": func(s string) (string, error) { return decoded(base64.StdEncoding.DecodeString(s)) },
+	"hexe": func(s string) (string, error) { return hex.EncodeToString([]byte(s)), nil },
+	"hexd": func(s string) (string, error) { return decoded(hex.DecodeString(s)) },
+}
+
+// decoded turns the bytes a decoder returned into text. Any decoding error
+// becomes errInvalidEncoding so no partial result is shown. Bytes that are
+// not UTF-8 are shown quoted, with escapes, so they cannot garble the
+// terminal.
+func decoded(b []byte, err error) (string, error) {
+	if err != nil {
+		return "", errInvalidEncoding
+	}
+	if !utf8.Valid(b) {
+		return fmt.Sprintf("%q", b), nil
+	}
+	return string(b), nil
+}
+
+// runEncode applies the codec commands the user types until "q".
+func runEncode(_ context.Context, env *Env) error {
+	r, w := env.In, env.Out
+	fmt.Fprintln(w, T("encode.welcome"))
+	for {
+		fmt.Fprint(w, env.Theme.prompt(T("encode.prompt")))
+		line, err := readLine(r)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// Only leading space is trimmed: trailing space is part of the text.
+		cmd, text, _ := strings.Cut(strings.TrimLeftFunc(line, unicode.IsSpace), " ")
+
+		switch cmd = strings.TrimSpace(cmd); cmd {
+		case "":
+			continue
+		case "q":
+			return nil
+		case "help":
+			fmt.Fprintln(w, translate("app.encode.usage", encodeHelp))
+			continue
+		}
+		codec, ok := codecs[cmd]
+		if !ok {
+			printError(w, unknownCommand(cmd, encodeCommands))
+			continue
+		}
+		out, err := codec(text)
+		if err != nil {
+			printError(w, err)
+			continue
+		}
+		env.Log.Info("encoded", "command", cmd, "text", text, "result", out)
+		fmt.Fprintln(w, out)
+	}
+}
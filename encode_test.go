@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestCodecsRoundTrip(t *testing.T) {
+	for _, s := range []string{"", "hi", "hello, world", "  spaces kept  ", "héllo wörld", "日本語", "emoji 🎲", "line\nbreak"} {
+		for _, pair := range [][2]string{{"b64e", "b64d"}, {"hexe", "hexd"}} {
+			enc, err := codecs[pair[0]](s)
+			if err != nil {
+				t.Errorf("%s(%q) failed: %v", pair[0], s, err)
+				continue
+			}
+			if got, err := codecs[pair[1]](enc); err != nil || got != s {
+				t.Errorf("%s(%s(%q)) = %q, %v", pair[1], pair[0], s, got, err)
+			}
+		}
+	}
+}
+
+func TestCodecs(t *testing.T) {
+	tests := []struct {
+		cmd, in, want string
+	}{
+		{"b64e", "hi", "aGk="},
+		{"b64e", "é", "w6k="},
+		{"b64d", "aGVsbG8=", "hello"},
+		{"hexe", "hi", "6869"},
+		{"hexd", "c3a9", "é"},
+		{"hexd", "ff00", `"\xff\x00"`}, // not UTF-8, so quoted
+	}
+	for _, tt := range tests {
+		if got, err := codecs[tt.cmd](tt.in); err != nil || got != tt.want {
+			t.Errorf("%s(%q) = %q, %v; want %q", tt.cmd, tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range [][2]string{{"b64d", "not base64!"}, {"b64d", "aGk"}, {"hexd", "abc"}, {"hexd", "zz"}} {
+		if got, err := codecs[bad[0]](bad[1]); err != errInvalidEncoding || got != "" {
+			t.Errorf("%s(%q) = %q, %v; want errInvalidEncoding", bad[0], bad[1], got, err)
+		}
+	}
+}
+
+func TestRunEncode(t *testing.T) {
+	var out bytes.Buffer
+	env := &Env{In: strings.NewReader(" b64e hi\n\thexe a b \nb64d !!\n   \nq\n"), Out: &out, Log: slog.New(discardHandler{})}
+	if err := runEncode(context.Background(), env); err != nil {
+		t.Fatal(err)
+	}
+	// Leading space is not a command; the text keeps its own spaces.
+	checkInOrder(t, out.String(), "aGk=\n", hex.EncodeToString([]byte("a b "))+"\n", "Error: invalid encoding")
+	if strings.Contains(out.String(), "unknown command") {
+		t.Errorf("a blank or indented line was taken for a command:\n%s", out.String())
+	}
+}
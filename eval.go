@@ -0,0 +1,634 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var errDivideByZero = newError("eval.divide_by_zero")
+
+// constants are the names that always have a value.
+var constants = map[string]float64{
+	"pi": math.Pi,
+	"e":  math.E,
+}
+
+// functions are the names that can be called as name(x). Add an entry here
+// to make a new function available. Trigonometry works in radians; see
+// callNode.eval for degree mode.
+var functions = map[string]func(float64) (float64, error){
+	"sqrt": func(x float64) (float64, error) {
+		if x < 0 {
+			return 0, newError("eval.sqrt_negative", x)
+		}
+		return math.Sqrt(x), nil
+	},
+	"sin":  pure(math.Sin),
+	"cos":  pure(math.Cos),
+	"tan":  pure(math.Tan),
+	"asin": pure(math.Asin),
+	"acos": pure(math.Acos),
+	"atan": pure(math.Atan),
+	"log":  positive("log", math.Log10),
+	"ln":   positive("ln", math.Log),
+	"exp":  pure(math.Exp),
+	"fact": factorial,
+}
+
+// In degree mode the arguments of angleArgs and the results of
+// angleResults are in degrees instead of radians.
+var (
+	angleArgs    = map[string]bool{"sin": true, "cos": true, "tan": true}
+	angleResults = map[string]bool{"asin": true, "acos": true, "atan": true}
+)
+
+// toRadians converts an angle in degrees to radians.
+func toRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+// fromRadians converts an angle in radians to degrees.
+func fromRadians(radians float64) float64 {
+	return radians * 180 / math.Pi
+}
+
+// roundTrig rounds off the error converting between degrees and radians
+// leaves in a trigonometry result, which would make cos(90) 6.1e-17 rather
+// than 0 and acos(0.5) 59.99999999999999 rather than 60.
+func roundTrig(v float64) float64 {
+	if math.Abs(v) < 1e-15 {
+		return 0
+	}
+	r, _ := strconv.ParseFloat(strconv.FormatFloat(v, 'g', 15, 64), 64)
+	return r
+}
+
+// factorial computes x! for whole numbers x >= 0. Results too large for a
+// float64 come out as +Inf, which evaluate reports.
+func factorial(x float64) (float64, error) {
+	if x < 0 || x != math.Trunc(x) {
+		return 0, newError("eval.fact_whole", x)
+	}
+	result := 1.0
+	for i := 2.0; i <= x && !math.IsInf(result, 1); i++ {
+		result *= i
+	}
+	return result, nil
+}
+
+// pure adapts a function that cannot fail for the functions table.
+func pure(f func(float64) float64) func(float64) (float64, error) {
+	return func(x float64) (float64, error) { return f(x), nil }
+}
+
+// positive adapts a function that is only defined for x > 0.
+func positive(name string, f func(float64) float64) func(float64) (float64, error) {
+	return func(x float64) (float64, error) {
+		if x <= 0 {
+			return 0, newError("eval.non_positive", name, x)
+		}
+		return f(x), nil
+	}
+}
+
+type tokenKind int
+
+const (
+	tokNumber tokenKind = iota
+	tokIdent
+	tokOperator
+	tokLParen
+	tokRParen
+	tokEOF
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // offset in runes in the expression, used in error messages
+}
+
+// tokenize splits expr into numbers, names, operators and parentheses.
+// Whitespace is ignored. Expressions are read a rune at a time, so names
+// may use any letters and positions count characters rather than bytes.
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	next := func(i int) (rune, int) {
+		if i >= len(expr) {
+			return 0, 0
+		}
+		return utf8.DecodeRuneInString(expr[i:])
+	}
+	add := func(kind tokenKind, start, end int) {
+		tokens = append(tokens, token{kind, expr[start:end], utf8.RuneCountInString(expr[:start])})
+	}
+	for i := 0; i < len(expr); {
+		c, size := next(i)
+		start := i
+		switch {
+		case unicode.IsSpace(c):
+			i += size
+		case c == '0' && i+1 < len(expr) && strings.ContainsRune("xXoObB", rune(expr[i+1])):
+			// 0x1F, 0o17 or 0b1010; the digits are checked when parsed.
+			i += 2
+			for c, size := next(i); isAlnum(c); c, size = next(i) {
+				i += size
+			}
+			add(tokNumber, start, i)
+		case unicode.IsDigit(c) || c == '.':
+			for c, size := next(i); unicode.IsDigit(c) || c == '.'; c, size = next(i) {
+				i += size
+			}
+			add(tokNumber, start, i)
+		case unicode.IsLetter(c):
+			for c, size := next(i); isAlnum(c); c, size = next(i) {
+				i += size
+			}
+			kind := tokIdent
+			if expr[start:i] == "xor" {
+				kind = tokOperator
+			}
+			add(kind, start, i)
+		case strings.HasPrefix(expr[i:], "<<") || strings.HasPrefix(expr[i:], ">>"):
+			i += 2
+			add(tokOperator, start, i)
+		case strings.ContainsRune("+-*/&|^!%=", c):
+			i += size
+			add(tokOperator, start, i)
+		case c == '(':
+			i += size
+			add(tokLParen, start, i)
+		case c == ')':
+			i += size
+			add(tokRParen, start, i)
+		default:
+			return nil, newError("eval.bad_char", c, utf8.RuneCountInString(expr[:i])+1)
+		}
+	}
+	return append(tokens, token{tokEOF, "", utf8.RuneCountInString(expr)}), nil
+}
+
+func isAlnum(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c)
+}
+
+// env is what names in an expression are resolved against.
+type env struct {
+	vars     *SymbolTable
+	memory   float64 // what MR refers to
+	ans      float64 // the previous result
+	hasAns   bool
+	ansExact *big.Int // the previous result if it came from big mode
+	degrees  bool     // trigonometry in degrees rather than radians
+
+	// continues is set when the expression starts with a binary operator
+	// that applies to ans, as in "+ 20".
+	continues bool
+}
+
+// node is one part of a parsed expression. eval computes it with
+// float64s and evalBig, in bigeval.go, exactly with integers.
+type node interface {
+	eval(e *env) (float64, error)
+	evalBig(e *env) (*big.Int, error)
+}
+
+type numberNode struct {
+	text  string // as written, so big mode can read every digit
+	value float64
+}
+
+func (n numberNode) eval(e *env) (float64, error) {
+	return n.value, nil
+}
+
+type identNode string
+
+func (n identNode) eval(e *env) (float64, error) {
+	if v, ok := constants[string(n)]; ok {
+		return v, nil
+	}
+	if strings.EqualFold(string(n), "mr") {
+		return e.memory, nil
+	}
+	if n == "ans" {
+		if !e.hasAns {
+			return 0, newError("eval.no_ans")
+		}
+		return e.ans, nil
+	}
+	if v, ok := e.vars.Get(string(n)); ok {
+		return v, nil
+	}
+	return 0, newError("eval.undefined", string(n))
+}
+
+// assignNode is name = x. Its value is the value assigned.
+type assignNode struct {
+	name string
+	x    node
+}
+
+func (n assignNode) eval(e *env) (float64, error) {
+	v, err := operand(n.x).eval(e)
+	if err != nil {
+		return 0, err
+	}
+	return v, e.vars.Set(n.name, v)
+}
+
+type callNode struct {
+	name string
+	arg  node
+}
+
+func (n callNode) eval(e *env) (float64, error) {
+	x, err := operand(n.arg).eval(e)
+	if err != nil {
+		return 0, err
+	}
+	if !e.degrees || !angleArgs[n.name] && !angleResults[n.name] {
+		return function(n.name)(x)
+	}
+	// tan(90) in radians is a huge number rather than an error, as pi/2
+	// is not exact, but in degrees it is exact and has no tangent.
+	if n.name == "tan" && math.Abs(math.Mod(x, 180)) == 90 {
+		return 0, newError("eval.tan_undefined", x)
+	}
+	if angleArgs[n.name] {
+		x = toRadians(x)
+	}
+	v, err := function(n.name)(x)
+	if err != nil {
+		return 0, err
+	}
+	if angleResults[n.name] {
+		v = fromRadians(v)
+	}
+	return roundTrig(v), nil
+}
+
+type unaryNode struct {
+	op string
+	x  node
+}
+
+func (n unaryNode) eval(e *env) (float64, error) {
+	x, err := operand(n.x).eval(e)
+	if err != nil {
+		return 0, err
+	}
+	if n.op == "-" {
+		return -x, nil
+	}
+	return x, nil
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n binaryNode) eval(e *env) (float64, error) {
+	a, err := operand(n.left).eval(e)
+	if err != nil {
+		return 0, err
+	}
+	b, err := operand(n.right).eval(e)
+	if err != nil {
+		return 0, err
+	}
+	switch n.op {
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/":
+		if b == 0 {
+			return 0, errDivideByZero
+		}
+		return a / b, nil
+	case "^":
+		return math.Pow(a, b), nil
+	}
+
+	// The rest are bitwise and only make sense for whole numbers.
+	x, err := toInt(n.op, a)
+	if err != nil {
+		return 0, err
+	}
+	y, err := toInt(n.op, b)
+	if err != nil {
+		return 0, err
+	}
+	switch n.op {
+	case "&":
+		return float64(x & y), nil
+	case "|":
+		return float64(x | y), nil
+	case "xor":
+		return float64(x ^ y), nil
+	case "<<", ">>":
+		if y < 0 {
+			return 0, newError("eval.negative_shift", y)
+		}
+		if n.op == "<<" {
+			if y >= 64 || (x<<y)>>y != x {
+				return 0, newError("eval.too_large")
+			}
+			return float64(x << y), nil
+		}
+		return float64(x >> y), nil
+	}
+	return 0, newError("eval.unknown_op", n.op)
+}
+
+// maxExactInt is the largest magnitude below which a float64 holds every
+// whole number exactly.
+const maxExactInt = 1 << 53
+
+// toInt converts the operand of a bitwise operator to an integer. Beyond
+// maxExactInt the float64 has already lost the low bits, so those are
+// refused rather than operated on.
+func toInt(op string, v float64) (int64, error) {
+	if v != math.Trunc(v) {
+		return 0, newError("eval.needs_whole", op, v)
+	}
+	if math.Abs(v) > maxExactInt {
+		return 0, newError("eval.int_too_large", op, v)
+	}
+	return int64(v), nil
+}
+
+// binaryLevels lists the left associative binary operators from loosest
+// to tightest binding. "^" (power) binds tighter still and is handled by
+// parsePower.
+var binaryLevels = [][]string{
+	{"|"},
+	{"xor"},
+	{"&"},
+	{"<<", ">>"},
+	{"+", "-"},
+	{"*", "/"},
+}
+
+// parser is a recursive descent parser over the grammar:
+//
+//	statement = [ name "=" ] expr
+//	expr      = binary(0)
+//	binary(i) = binary(i+1) { binaryLevels[i] binary(i+1) }  (binary(len(binaryLevels)) is unary)
+//	unary     = ("+" | "-") unary | power
+//	power     = postfix [ "^" unary ]
+//	postfix   = primary { "!" | "%" }
+//	primary   = number | name | name "(" expr ")" | "(" expr ")"
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) parseExpr() (node, error) {
+	return p.parseBinary(0)
+}
+
+// parseBinary parses operators of binaryLevels[level] and tighter.
+func (p *parser) parseBinary(level int) (node, error) {
+	if level == len(binaryLevels) {
+		return p.parseUnary()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t.kind == tokOperator && slices.Contains(binaryLevels[level], t.text); t = p.peek() {
+		p.next()
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{t.text, left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if t := p.peek(); t.kind == tokOperator && (t.text == "+" || t.text == "-") {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{t.text, x}, nil
+	}
+	return p.parsePower()
+}
+
+// parsePower parses x ^ y. It is right associative and binds tighter than
+// unary minus on its left, so -2^2 is -4 and 2^-1 is 0.5.
+func (p *parser) parsePower() (node, error) {
+	x, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind == tokOperator && t.text == "^" {
+		p.next()
+		y, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return binaryNode{"^", x, y}, nil
+	}
+	return x, nil
+}
+
+// parsePostfix parses factorials such as 5! and percentages such as 15%,
+// which is 15 / 100.
+func (p *parser) parsePostfix() (node, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t.kind == tokOperator && (t.text == "!" || t.text == "%"); t = p.peek() {
+		p.next()
+		if t.text == "!" {
+			x = callNode{"fact", x}
+		} else {
+			x = binaryNode{"/", x, numberNode{"100", 100}}
+		}
+	}
+	return x, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		v, err := parseNumber(t.text)
+		if err != nil {
+			return nil, newError("eval.bad_number", t.text, t.pos+1)
+		}
+		return numberNode{t.text, v}, nil
+	case tokIdent:
+		if p.peek().kind != tokLParen {
+			return identNode(t.text), nil
+		}
+		if _, ok := functions[t.text]; !ok {
+			return nil, newError("eval.unknown_func", t.text, t.pos+1)
+		}
+		arg, err := p.parseParens(p.next())
+		if err != nil {
+			return nil, err
+		}
+		return callNode{t.text, arg}, nil
+	case tokLParen:
+		return p.parseParens(t)
+	case tokEOF:
+		return nil, newError("eval.unexpected_end")
+	}
+	return nil, newError("eval.unexpected", t.text, t.pos+1)
+}
+
+// parseNumber parses a decimal number or a 0x, 0o or 0b integer literal.
+func parseNumber(s string) (float64, error) {
+	if hasBasePrefix(s) {
+		i, err := strconv.ParseInt(s, 0, 64)
+		return float64(i), err
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// hasBasePrefix reports whether s starts like 0x, 0o or 0b.
+func hasBasePrefix(s string) bool {
+	return len(s) > 1 && s[0] == '0' && unicode.IsLetter(rune(s[1]))
+}
+
+// parseParens parses the rest of "(" expr ")" once open has been read.
+func (p *parser) parseParens(open token) (node, error) {
+	x, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if closing := p.next(); closing.kind != tokRParen {
+		return nil, newError("eval.missing_paren", open.pos+1)
+	}
+	return x, nil
+}
+
+// parse turns expr into a tree that can be evaluated. If continues is set,
+// expr is read as if it began with ans.
+func parse(expr string, continues bool) (node, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	if tokens[0].kind == tokEOF {
+		return nil, newError("eval.empty")
+	}
+	if continues {
+		tokens = slices.Insert(tokens, 0, token{tokIdent, "ans", 0})
+	}
+	p := &parser{tokens: tokens}
+	var assignTo string
+	if len(tokens) > 2 && tokens[0].kind == tokIdent && tokens[1].text == "=" {
+		assignTo = tokens[0].text
+		p.pos = 2
+	}
+	n, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if assignTo != "" {
+		n = assignNode{assignTo, n}
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		if t.kind == tokRParen {
+			return nil, newError("eval.unmatched_paren", t.pos+1)
+		}
+		return nil, newError("eval.unexpected", t.text, t.pos+1)
+	}
+	return n, nil
+}
+
+// Evaluate parses and computes an arithmetic expression such as
+// "2 + 3 * 4 - 1", honouring operator precedence and parentheses.
+func Evaluate(expr string) (float64, error) {
+	return evaluate(expr, &env{})
+}
+
+// evaluate is Evaluate with names resolved against e.
+func evaluate(expr string, e *env) (_ float64, err error) {
+	defer recoverEval(&err)
+	n, err := parse(expr, e.continues)
+	if err != nil {
+		return 0, err
+	}
+	v, err := n.eval(e)
+	switch {
+	case err != nil:
+		return 0, err
+	case math.IsInf(v, 0):
+		return 0, newError("eval.too_large")
+	case math.IsNaN(v):
+		return 0, newError("eval.not_real")
+	}
+	return v, nil
+}
+
+// malformed is the panic value of node evaluation that finds the tree
+// malformed: a missing operand or a call of a function that does not exist.
+// The parser never builds such trees, so this is a bug in whatever built
+// it, but one confined to the expression.
+type malformed string
+
+// operand returns n, an operand of the node being evaluated, and panics
+// with malformed if it is missing.
+func operand(n node) node {
+	if n == nil {
+		panic(malformed("missing operand"))
+	}
+	return n
+}
+
+// function returns the function called name and panics with malformed if
+// there is none.
+func function(name string) func(float64) (float64, error) {
+	f, ok := functions[name]
+	if !ok {
+		panic(malformed(fmt.Sprintf("no function %q", name)))
+	}
+	return f
+}
+
+// recoverEval is deferred by the evaluators to turn a malformed panic into
+// an error in *err. The calculator then reports it like any other bad
+// expression. Any other panic is a bug outside the tree and is not hidden.
+func recoverEval(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if m, ok := r.(malformed); ok {
+		*err = newError("eval.internal", string(m))
+		return
+	}
+	panic(r)
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		expr string
+		want float64
+	}{
+		{"42", 42},
+		{"1.5", 1.5},
+		{"2 + 3 * 4", 14},
+		{"2 * 3 + 4", 10},
+		{"10 - 4 / 2", 8},
+		{"(2 + 3) * 4", 20},
+		{"8 - 3 - 2", 3},
+		{"100 / 10 / 5", 2},
+		{"2^3^2", 512},
+		{"(2^3)^2", 64},
+		{"2 * 3^2", 18},
+		{"-3", -3},
+		{"--3", 3},
+		{"+3", 3},
+		{"-2^2", -4},
+		{"2^-1", 0.5},
+		{"2 * -3", -6},
+		{"-(2 + 3)", -5},
+		{"((1 + 2) * (3 + 4))", 21},
+		{"(((7)))", 7},
+		{"2 * (3 + (4 - 1)) / 3", 4},
+		{"5!", 120},
+		{"3!^2", 36},
+		{"50%", 0.5},
+		{"200 * 15%", 30},
+		{"1 + 2 << 1", 6},
+		{"6 & 3 | 8", 10},
+		{"6 xor 3", 5},
+		{"0x1F + 0o17 + 0b11", 49},
+		{"0xFF & 0x0F", 15},
+		{"0xF0 | 0x0F", 255},
+		{"1 << 62", 1 << 62},
+		{"-1 << 63", -1 << 63},
+		{"(2^53 - 1) | 0", 1<<53 - 1},
+		{"-2^53 & -1", -1 << 53},
+		{"pi - pi", 0},
+	}
+	for _, tt := range tests {
+		got, err := Evaluate(tt.expr)
+		if err != nil {
+			t.Errorf("Evaluate(%q) failed: %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Evaluate(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"", "empty expression"},
+		{"   ", "empty expression"},
+		{"1 / 0", "cannot divide by zero"},
+		{"1 / (2 - 2)", "cannot divide by zero"},
+		{"sqrt(-4)", "sqrt of negative number -4"},
+		{"fact(2.5)", "factorial needs a whole number >= 0, got 2.5"},
+		{"(-1)!", "factorial needs a whole number >= 0, got -1"},
+		{"log(0)", "log of non-positive number 0"},
+		{"ln(-1)", "ln of non-positive number -1"},
+		{"2 $ 3", "unexpected character '$' at position 3"},
+		{"2 + é", "undefined variable: é"},
+		{"π", "undefined variable: π"},
+		{"2 + €", "unexpected character '€' at position 5"},
+		{"é + 2 $", "unexpected character '$' at position 7"},
+		{"√4", "unexpected character '√' at position 1"},
+		{"ñ)", "unmatched ')' at position 2"},
+		{"ans + 1", "ans: there is no previous result yet"},
+		{"y + 1", "undefined variable: y"},
+		{"1 << -1", "negative shift count -1"},
+		{"1.5 & 1", "operator & needs whole numbers, got 1.5"},
+		{"2^53 + 2 | 0", "operator | needs whole numbers up to 2^53, got 9.007199254740994e+15"},
+		{"2^63 & 1", "operator & needs whole numbers up to 2^53, got 9.223372036854776e+18"},
+		{"1 << 63", "result is too large"},
+		{"1 << 64", "result is too large"},
+		{"3 << 62", "result is too large"},
+		{"1.2.3", `invalid number "1.2.3" at position 1`},
+		{"0xZZ", `invalid number "0xZZ" at position 1`},
+		{"foo(1)", `unknown function "foo" at position 1`},
+		{"2 *", "unexpected end of expression"},
+		{"* 2", `unexpected "*" at position 1`},
+		{"2 3", `unexpected "3" at position 3`},
+		{"(1 + 2", "missing closing parenthesis for '(' at position 1"},
+		{"1 + 2)", "unmatched ')' at position 6"},
+		{"10^400", "result is too large"},
+		{"(-8)^(1/3)", "result is not a real number"},
+		{"pi = 3", `cannot assign to reserved name "pi"`},
+		{"x = 3", `cannot assign to "x": variables are not available here`},
+	}
+	for _, tt := range tests {
+		_, err := Evaluate(tt.expr)
+		if err == nil {
+			t.Errorf("Evaluate(%q) succeeded, want error %q", tt.expr, tt.want)
+			continue
+		}
+		if err.Error() != tt.want {
+			t.Errorf("Evaluate(%q) error = %q, want %q", tt.expr, err, tt.want)
+		}
+	}
+}
+
+func TestEvaluateFunctions(t *testing.T) {
+	tests := []struct {
+		expr string
+		want float64
+	}{
+		{"sqrt(16)", 4},
+		{"sqrt(2)", math.Sqrt2},
+		{"sin(0)", 0},
+		{"sin(pi/2)", 1},
+		{"cos(0)", 1},
+		{"cos(pi)", -1},
+		{"tan(0)", 0},
+		{"tan(pi/4)", 1},
+		{"asin(1)", math.Pi / 2},
+		{"acos(1)", 0},
+		{"atan(1)", math.Pi / 4},
+		{"log(1000)", 3},
+		{"ln(e)", 1},
+		{"exp(0)", 1},
+		{"exp(1)", math.E},
+		{"fact(5)", 120},
+		{"fact(0)", 1},
+		{"sqrt(sqrt(16)) * 2", 4},
+	}
+	for _, tt := range tests {
+		got, err := Evaluate(tt.expr)
+		if err != nil {
+			t.Errorf("Evaluate(%q) failed: %v", tt.expr, err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("Evaluate(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+	if got, err := Evaluate("sqrt(16) + sin(pi/2)"); err != nil || got != 5 {
+		t.Errorf("Evaluate(sqrt(16) + sin(pi/2)) = %v, %v; want exactly 5", got, err)
+	}
+}
+
+// panicNode is a node whose evaluation panics with v.
+type panicNode struct{ v any }
+
+func (n panicNode) eval(*env) (float64, error)     { panic(n.v) }
+func (n panicNode) evalBig(*env) (*big.Int, error) { panic(n.v) }
+
+// evalTree evaluates n the way evaluate does once it has parsed.
+func evalTree(n node) (_ float64, err error) {
+	defer recoverEval(&err)
+	return n.eval(&env{})
+}
+
+func TestEvalMalformedTree(t *testing.T) {
+	tests := []struct {
+		name string
+		n    node
+		want string
+	}{
+		{"binary without right", binaryNode{op: "+", left: numberNode{"1", 1}}, "could not evaluate this expression (missing operand)"},
+		{"binary without left", binaryNode{op: "*", right: numberNode{"1", 1}}, "could not evaluate this expression (missing operand)"},
+		{"unary without operand", unaryNode{op: "-"}, "could not evaluate this expression (missing operand)"},
+		{"call without argument", callNode{name: "sqrt"}, "could not evaluate this expression (missing operand)"},
+		{"unknown function", callNode{name: "nope", arg: numberNode{"1", 1}}, `could not evaluate this expression (no function "nope")`},
+		{"deep inside", binaryNode{op: "+", left: numberNode{"1", 1}, right: unaryNode{op: "-"}}, "could not evaluate this expression (missing operand)"},
+	}
+	for _, tt := range tests {
+		_, err := evalTree(tt.n)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestEvalOtherPanics(t *testing.T) {
+	// Only malformed trees are turned into errors; other panics, even
+	// runtime errors, are bugs that must not be hidden.
+	for _, v := range []any{"boom", indexPanic(), errDivideByZero} {
+		func() {
+			defer func() {
+				if r := recover(); r != v {
+					t.Errorf("recovered %v, want the panic %v to get through", r, v)
+				}
+			}()
+			evalTree(binaryNode{op: "+", left: numberNode{"1", 1}, right: panicNode{v}})
+			t.Errorf("panic %v was turned into an error", v)
+		}()
+	}
+}
+
+// indexPanic returns the runtime error of indexing out of range.
+func indexPanic() (r any) {
+	defer func() { r = recover() }()
+	var xs []int
+	_ = xs[len(xs)]
+	return nil
+}
+
+func TestAngleConversion(t *testing.T) {
+	tests := []struct {
+		degrees, radians float64
+	}{
+		{0, 0},
+		{90, math.Pi / 2},
+		{180, math.Pi},
+		{-45, -math.Pi / 4},
+		{360, 2 * math.Pi},
+	}
+	for _, tt := range tests {
+		if got := toRadians(tt.degrees); math.Abs(got-tt.radians) > 1e-15 {
+			t.Errorf("toRadians(%v) = %v, want %v", tt.degrees, got, tt.radians)
+		}
+		if got := fromRadians(tt.radians); math.Abs(got-tt.degrees) > 1e-12 {
+			t.Errorf("fromRadians(%v) = %v, want %v", tt.radians, got, tt.degrees)
+		}
+	}
+}
+
+func TestRoundTrig(t *testing.T) {
+	tests := []struct {
+		v, want float64
+	}{
+		{math.Cos(toRadians(90)), 0},
+		{-1e-16, 0},
+		{fromRadians(math.Acos(0.5)), 60},
+		{math.Sin(toRadians(30)), 0.5},
+		{1, 1},
+		{0.1234567890123456, 0.123456789012346},
+		{1e-14, 1e-14}, // small, but not an error
+	}
+	for _, tt := range tests {
+		if got := roundTrig(tt.v); got != tt.want {
+			t.Errorf("roundTrig(%v) = %v, want %v", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateDegrees(t *testing.T) {
+	tests := []struct {
+		expr string
+		want float64
+	}{
+		{"sin(90)", 1},
+		{"cos(90)", 0},
+		{"cos(180)", -1},
+		{"tan(45)", 1},
+		{"tan(-45)", -1},
+		{"tan(180)", 0},
+		{"sin(30)", 0.5},
+		{"asin(1)", 90},
+		{"acos(0.5)", 60},
+		{"atan(1)", 45},
+		{"sin(asin(0.5))", 0.5},
+		// Only angles are converted.
+		{"sqrt(16) + exp(0)", 5},
+		{"pi", math.Pi},
+	}
+	for _, tt := range tests {
+		got, err := evaluate(tt.expr, &env{degrees: true})
+		if err != nil || got != tt.want {
+			t.Errorf("evaluate(%q) in degrees = %v, %v; want %v", tt.expr, got, err, tt.want)
+		}
+	}
+
+	// Odd multiples of 90 degrees have no tangent. In radians 90 is just
+	// a number like any other.
+	for _, x := range []string{"90", "270", "-90", "450", "-270"} {
+		_, err := evaluate("tan("+x+")", &env{degrees: true})
+		if want := "tan of " + x + " degrees is undefined"; err == nil || err.Error() != want {
+			t.Errorf("tan(%s) in degrees: err = %v, want %q", x, err, want)
+		}
+	}
+	if _, err := evaluate("tan(90)", &env{}); err != nil {
+		t.Errorf("tan(90) in radians: %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+)
+
+// dataPath returns the path of name inside the ~/.demo-go directory,
+// creating the directory if it does not exist yet.
+func dataPath(name string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(home, ".demo-go")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it into place, so a crash part way through never leaves path half written.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) // no-op once the rename has happened
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// writeCSV writes a header row and then rows to path as CSV, replacing any
+// file already there. Fields with commas, quotes or newlines are quoted.
+func writeCSV(path string, header []string, rows [][]string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(f)
+	if err := cw.Write(header); err != nil {
+		f.Close()
+		return err
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
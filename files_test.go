@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWriteCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.csv")
+	header := []string{"expr", "result"}
+	rows := [][]string{
+		{"1 + 2", "3"},
+		{"1,000 * 2", "2000"},
+		{`say "hi"`, `"quoted", too`},
+		{"two\nlines", ""},
+	}
+	if err := os.WriteFile(path, []byte("old contents\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeCSV(path, header, rows); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	got, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := append([][]string{header}, rows...); !reflect.DeepEqual(got, want) {
+		t.Errorf("read back %q, want %q", got, want)
+	}
+
+	data, _ := os.ReadFile(path)
+	const raw = "expr,result\n1 + 2,3\n\"1,000 * 2\",2000\n\"say \"\"hi\"\"\",\"\"\"quoted\"\", too\"\n\"two\nlines\",\n"
+	if string(data) != raw {
+		t.Errorf("file is %q, want %q", data, raw)
+	}
+}
+
+func TestWriteCSVNoDirectory(t *testing.T) {
+	if err := writeCSV(filepath.Join(t.TempDir(), "missing", "out.csv"), []string{"a"}, nil); err == nil {
+		t.Error("writeCSV into a missing directory succeeded")
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.json")
+	for _, data := range []string{"first\n", "second\n"} {
+		if err := writeFileAtomic(path, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := os.ReadFile(path); string(got) != data {
+			t.Errorf("file is %q, want %q", got, data)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+}
@@ -1,3 +1,10 @@
 module demo-go
 
 go 1.23.2
+
+require (
+	golang.org/x/crypto v0.36.0
+	golang.org/x/term v0.34.0
+)
+
+require golang.org/x/sys v0.35.0 // indirect
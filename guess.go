@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register(App{
+		ID:          "guess",
+		Name:        "Guess the Number",
+		Description: "find the number between 1 and 100 the computer picked",
+		Usage:       guessHelp,
+		Run:         runGuess,
+	})
+}
+
+// guessHelp is the game's usage, printed by its "help" command.
+const guessHelp = `Type a whole number from 1 to 100. You are told whether the number
+is higher or lower, until you get it. Type q to give up.`
+
+// runGuess plays a guess-the-number game with a time-based seed.
+func runGuess(_ context.Context, env *Env) error {
+	return playGuess(env.In, env.Out, env.Log, time.Now().UnixNano())
+}
+
+// playGuess picks a number in [1,100] from seed and reads guesses from r
+// until one is right or the user types "q".
+func playGuess(r io.Reader, w io.Writer, log *slog.Logger, seed int64) error {
+	target := rand.New(rand.NewSource(seed)).Intn(100) + 1
+	fmt.Fprintln(w, T("guess.welcome"))
+	for tries := 1; ; {
+		fmt.Fprintln(w, T("guess.prompt"))
+		line, err := readLine(r)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		if line == "q" {
+			return nil
+		}
+		if line == "help" {
+			fmt.Fprintln(w, translate("app.guess.usage", guessHelp))
+			continue
+		}
+		guess, err := parseInt(line, 1, 100)
+		if err != nil {
+			printError(w, err)
+			continue
+		}
+
+		switch {
+		case guess < target:
+			fmt.Fprintln(w, T("guess.higher"))
+		case guess > target:
+			fmt.Fprintln(w, T("guess.lower"))
+		default:
+			log.Info("guessed", "number", target, "tries", tries)
+			fmt.Fprintf(w, T("guess.correct")+"\n", tries)
+			return nil
+		}
+		tries++
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestPlayGuess(t *testing.T) {
+	// Seed 1 makes the computer pick 82.
+	tests := []struct {
+		name  string
+		input string
+		want  []string // printed in this order
+	}{
+		{"found", "50\n90\n82\n", []string{"higher", "lower", "correct in 3 tries"}},
+		{"first try", "82\n", []string{"correct in 1 tries"}},
+		{"bad guesses are not counted", "0\nabc\n82\n", []string{"Error:", "Error:", "correct in 1 tries"}},
+		{"give up", "50\nq\n", []string{"higher"}},
+		{"help", "help\n82\n", []string{"Type a whole number", "correct in 1 tries"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := playGuess(strings.NewReader(tt.input), &out, slog.New(discardHandler{}), 1); err != nil {
+				t.Fatal(err)
+			}
+			checkInOrder(t, out.String(), tt.want...)
+		})
+	}
+}
+
+func TestPlayGuessEndOfInput(t *testing.T) {
+	var out bytes.Buffer
+	if err := playGuess(strings.NewReader("50\n"), &out, slog.New(discardHandler{}), 1); err != nil {
+		t.Fatalf("playGuess at the end of the input: %v", err)
+	}
+	if strings.Contains(out.String(), "correct") {
+		t.Errorf("game was won without the right guess:\n%s", out.String())
+	}
+}
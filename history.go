@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"math/big"
+	"os"
+	"strconv"
+	"time"
+)
+
+// maxHistory is how many calculations are kept; older ones are dropped.
+const maxHistory = 100
+
+// HistoryEntry is one evaluated expression and its result.
+type HistoryEntry struct {
+	Time   time.Time `json:"time"`
+	Expr   string    `json:"expr"`
+	Result float64   `json:"result"`          // 0 if Exact does not fit in a float64
+	Exact  string    `json:"exact,omitempty"` // decimal digits of a big mode result
+}
+
+// history holds the calculations, oldest first.
+var history []HistoryEntry
+
+// AppendHistory records a calculation, evicting the oldest entry once
+// there are more than maxHistory.
+func AppendHistory(expr string, result float64) {
+	appendEntry(HistoryEntry{Time: time.Now(), Expr: expr, Result: result})
+}
+
+// AppendBigHistory records a calculation made in big mode.
+func AppendBigHistory(expr string, result *big.Int) {
+	f, _ := new(big.Float).SetInt(result).Float64()
+	if math.IsInf(f, 0) {
+		f = 0 // JSON has no infinity
+	}
+	appendEntry(HistoryEntry{Time: time.Now(), Expr: expr, Result: f, Exact: result.String()})
+}
+
+func appendEntry(entry HistoryEntry) {
+	history = append(history, entry)
+	if len(history) > maxHistory {
+		history = history[len(history)-maxHistory:]
+	}
+}
+
+// GetHistory returns a copy of the recorded calculations, oldest first.
+func GetHistory() []HistoryEntry {
+	return append([]HistoryEntry(nil), history...)
+}
+
+// LoadHistory reads history saved by SaveHistory. A missing file is not an
+// error and gives an empty history; a corrupt one gives an empty history
+// and the error.
+func LoadHistory(path string) ([]HistoryEntry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var h []HistoryEntry
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf(T("history.invalid"), path, err)
+	}
+	if len(h) > maxHistory {
+		h = h[len(h)-maxHistory:]
+	}
+	return h, nil
+}
+
+// SaveHistory writes h to path as indented JSON, replacing the file
+// atomically.
+func SaveHistory(path string, h []HistoryEntry) error {
+	if h == nil {
+		h = []HistoryEntry{} // write [] rather than null
+	}
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+// historyCSVHeader names the columns of historyRows.
+var historyCSVHeader = []string{"timestamp", "expression", "result"}
+
+// historyRows turns h into CSV rows, giving big mode results exactly.
+func historyRows(h []HistoryEntry) [][]string {
+	rows := make([][]string, len(h))
+	for i, e := range h {
+		result := strconv.FormatFloat(e.Result, 'g', -1, 64)
+		if e.Exact != "" {
+			result = e.Exact
+		}
+		rows[i] = []string{e.Time.Format(time.RFC3339), e.Expr, result}
+	}
+	return rows
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// useHistory replaces the calculator history with h until t ends.
+func useHistory(t *testing.T, h []HistoryEntry) {
+	t.Helper()
+	saved := history
+	t.Cleanup(func() { history = saved })
+	history = h
+}
+
+func TestAppendHistory(t *testing.T) {
+	useHistory(t, nil)
+	for i := range maxHistory + 1 {
+		AppendHistory(fmt.Sprint(i), float64(i))
+	}
+	h := GetHistory()
+	if len(h) != maxHistory {
+		t.Fatalf("%d entries kept, want %d", len(h), maxHistory)
+	}
+	// The oldest, 0, was dropped; the rest are in order.
+	if h[0].Expr != "1" || h[len(h)-1].Expr != fmt.Sprint(maxHistory) || h[len(h)-1].Result != maxHistory {
+		t.Errorf("history runs from %+v to %+v, want 1 to %d", h[0], h[len(h)-1], maxHistory)
+	}
+	if h[0].Time.IsZero() {
+		t.Error("an entry has no time")
+	}
+}
+
+func TestGetHistoryCopies(t *testing.T) {
+	useHistory(t, nil)
+	if h := GetHistory(); len(h) != 0 {
+		t.Fatalf("empty history = %v", h)
+	}
+	AppendHistory("1 + 1", 2)
+	h := GetHistory()
+	h[0].Expr = "changed"
+	if got := GetHistory(); len(got) != 1 || got[0].Expr != "1 + 1" {
+		t.Errorf("changing a copy changed the history: %+v", got)
+	}
+}
+
+func TestLoadHistoryMissing(t *testing.T) {
+	h, err := LoadHistory(filepath.Join(t.TempDir(), "history.json"))
+	if err != nil || len(h) != 0 {
+		t.Errorf("LoadHistory(missing file) = %v, %v; want an empty history and no error", h, err)
+	}
+}
+
+func TestLoadHistoryCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	if err := os.WriteFile(path, []byte(`[{"expr": "1 + 1", "result": 2}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	h, err := LoadHistory(path)
+	if err == nil || !strings.Contains(err.Error(), "is not valid history") {
+		t.Errorf("LoadHistory(corrupt file) error = %v, want it reported", err)
+	}
+	if len(h) != 0 {
+		t.Errorf("LoadHistory(corrupt file) = %v, want an empty history", h)
+	}
+}
+
+func TestSaveHistoryRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "history.json")
+	when := time.Date(2024, 5, 1, 10, 30, 15, 123456789, time.FixedZone("CEST", 2*60*60))
+	h := []HistoryEntry{
+		{Time: when, Expr: "2 + 3 * 4", Result: 14},
+		{Time: when.Add(time.Second), Expr: "30!", Result: 2.652528598121911e32, Exact: "265252859812191058636308480000000"},
+	}
+	if err := os.WriteFile(path, []byte("old"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveHistory(path, h); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(h) {
+		t.Fatalf("loaded %d entries, want %d", len(got), len(h))
+	}
+	for i := range h {
+		if !got[i].Time.Equal(h[i].Time) || got[i].Expr != h[i].Expr || got[i].Result != h[i].Result || got[i].Exact != h[i].Exact {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], h[i])
+		}
+	}
+	// The file was replaced in one rename, leaving no temporary file behind.
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("files left after saving: %v", entries)
+	}
+
+	if err := SaveHistory(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "[]\n" {
+		t.Errorf("empty history saved as %q, want []", data)
+	}
+}
+
+func TestLoadHistoryKeepsNewest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	var h []HistoryEntry
+	for i := range maxHistory + 5 {
+		h = append(h, HistoryEntry{Expr: fmt.Sprint(i)})
+	}
+	if err := SaveHistory(path, h); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadHistory(path)
+	if err != nil || len(got) != maxHistory {
+		t.Fatalf("loading %d entries kept %d, %v; want %d", len(h), len(got), err, maxHistory)
+	}
+	if got[0].Expr != "5" {
+		t.Errorf("the oldest entry kept is %q, want 5", got[0].Expr)
+	}
+}
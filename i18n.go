@@ -0,0 +1,275 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// catalogs holds the user-facing text of each supported locale, keyed by
+// message. English is complete; other locales may leave keys out.
+var catalogs = map[string]map[string]string{
+	"en": english,
+	"es": spanish,
+}
+
+// locale is set once at startup by setLocale.
+var locale = "en"
+
+// setLocale selects the catalog T uses and reports whether name, a
+// language code such as "es" or a LANG value such as "es_ES.UTF-8", is
+// supported. An unsupported name leaves English selected.
+func setLocale(name string) bool {
+	lang, _, _ := strings.Cut(name, ".")
+	lang, _, _ = strings.Cut(lang, "_")
+	lang = strings.ToLower(lang)
+	if _, ok := catalogs[lang]; !ok {
+		locale = "en"
+		return false
+	}
+	locale = lang
+	return true
+}
+
+// T returns the text for key in the current locale, falling back to the
+// English text, or to key itself if there is none.
+func T(key string) string {
+	if s, ok := catalogs[locale][key]; ok {
+		return s
+	}
+	if s, ok := english[key]; ok {
+		return s
+	}
+	return key
+}
+
+// translate is T for keys that are optional in every locale, such as the
+// names of apps, which default to what the code says.
+func translate(key, fallback string) string {
+	if s, ok := catalogs[locale][key]; ok {
+		return s
+	}
+	return fallback
+}
+
+// msgError is an error whose text is looked up with T when it is printed,
+// so it follows the locale. Pointers to it are comparable, so sentinel
+// errors made with newError work with errors.Is.
+type msgError struct {
+	key  string
+	args []any
+}
+
+// newError returns an error whose text is fmt.Sprintf(T(key), args...).
+func newError(key string, args ...any) error {
+	return &msgError{key: key, args: args}
+}
+
+func (e *msgError) Error() string {
+	return fmt.Sprintf(T(e.key), e.args...)
+}
+
+// english is the catalog every other locale falls back to.
+var english = map[string]string{
+	"error":   "Error:",
+	"warning": "Warning:",
+
+	"menu.main":      "Main",
+	"menu.title":     "Choose app:",
+	"menu.exit":      "Exit",
+	"menu.back":      "Back",
+	"menu.exited":    "Exited",
+	"menu.exiting":   "Exiting...",
+	"menu.selected":  "  %s: selected %d time(s)",
+	"menu.cancelled": "Cancelled",
+	"menu.continue":  "Press Enter to continue...",
+	"menu.enter":     "Enter",
+	"menu.last_used": "%s (last used)",
+
+	"summary.duration":     "Session lasted %s.",
+	"summary.apps":         "Apps used:",
+	"summary.no_apps":      "No apps used.",
+	"summary.calculations": "Calculations: %d",
+
+	"help.apps":     "Apps:",
+	"help.commands": "Commands:",
+	"help.open":     "open the entry with that number",
+	"help.exit":     "exit",
+	"help.last":     "open %s again",
+	"help.back":     "back to the %s menu",
+	"help.help":     "show this help",
+	"help.version":  "show the version",
+	"help.inside":   `Inside an app, type "help" to see how to use it.`,
+
+	"auth.enter":    "Enter password:",
+	"auth.waiting":  "Waiting %v before checking...",
+	"auth.wrong":    "Wrong password, try again.",
+	"auth.none":     "No password set yet.",
+	"auth.choose":   "Choose a password:",
+	"auth.confirm":  "Confirm password:",
+	"auth.mismatch": "Passwords do not match.",
+	"auth.saved":    "Password saved.",
+	"auth.locked":   "Too many attempts, locked out",
+	"auth.bad":      "wrong password",
+
+	"password.length": "Password must be at least %d characters long.",
+	"password.digit":  "Password must contain at least one digit.",
+	"password.upper":  "Password must contain at least one uppercase letter.",
+	"password.symbol": "Password must contain at least one symbol.",
+
+	"calc.welcome":         `Calculator. Type "help" for help or "exit" to return to the menu.`,
+	"calc.expected_base":   "expected as hex, as oct, as bin or as dec",
+	"calc.expected_mode":   "expected mode big, float, deg or rad",
+	"calc.expected_format": "expected format followed by 0 to %d, auto, group on or group off",
+	"calc.fell_back":       "not an integer expression, using float mode",
+	"calc.no_vars":         "No variables yet.",
+	"calc.no_history":      "No calculations yet.",
+	"calc.no_result":       "no previous result",
+	"calc.no_continue":     "no previous result to continue from",
+
+	"eval.divide_by_zero":  "cannot divide by zero",
+	"eval.sqrt_negative":   "sqrt of negative number %v",
+	"eval.fact_whole":      "factorial needs a whole number >= 0, got %v",
+	"eval.fact_too_large":  "factorial of %v is too large (the limit is %d)",
+	"eval.non_positive":    "%s of non-positive number %v",
+	"eval.bad_char":        "unexpected character %q at position %d",
+	"eval.no_ans":          "ans: there is no previous result yet",
+	"eval.undefined":       "undefined variable: %s",
+	"eval.negative_shift":  "negative shift count %v",
+	"eval.shift_too_large": "shift count %v is too large",
+	"eval.unknown_op":      "unknown operator %q",
+	"eval.needs_whole":     "operator %s needs whole numbers, got %v",
+	"eval.int_too_large":   "operator %s needs whole numbers up to 2^53, got %v",
+	"eval.bad_number":      "invalid number %q at position %d",
+	"eval.unknown_func":    "unknown function %q at position %d",
+	"eval.unexpected_end":  "unexpected end of expression",
+	"eval.unexpected":      "unexpected %q at position %d",
+	"eval.missing_paren":   "missing closing parenthesis for '(' at position %d",
+	"eval.empty":           "empty expression",
+	"eval.unmatched_paren": "unmatched ')' at position %d",
+	"eval.too_large":       "result is too large",
+	"eval.not_real":        "result is not a real number",
+	"eval.tan_undefined":   "tan of %v degrees is undefined",
+	"eval.not_integer":     "not an integer expression",
+	"eval.pow_too_large":   "result of %v ^ %v is too large",
+	"eval.reserved":        "cannot assign to reserved name %q",
+	"eval.internal":        "could not evaluate this expression (%v)",
+	"eval.no_vars":         "cannot assign to %q: variables are not available here",
+
+	"number.group":   ",",
+	"number.decimal": ".",
+
+	"export.done":  "Wrote %d rows to %s",
+	"export.usage": "usage: export <path>",
+
+	"input.int_range": "please enter a whole number from %d to %d",
+	"input.too_many":  "too many invalid answers",
+
+	"undo.nothing": "nothing to undo",
+	"redo.nothing": "nothing to redo",
+
+	"main.reading_config":    "reading config:",
+	"main.disabled":          "%s is disabled in %s",
+	"main.opening_log":       "opening DEMO_LOG:",
+	"main.empty_history":     "starting with an empty history:",
+	"main.saving_history":    "saving history:",
+	"main.saving_config":     "saving config:",
+	"theme.unknown":          "unknown theme %q (want %s), using %s",
+	"history.invalid":        "%s is not valid history: %w",
+	"config.unknown_app":     "disabled: unknown app %q",
+	"config.calculator_mode": `calculator_mode: want "float" or "big", not %q`,
+	"config.input_timeout":   "input_timeout: want a number of seconds >= 0, not %d",
+
+	"command.unknown":      "unknown command %q",
+	"command.did_you_mean": "unknown command %q, did you mean %q?",
+
+	"input.bad_number": "invalid number %q",
+	"input.timeout":    "timed out waiting for input",
+
+	"convert.units":         "Units: m ft mi (length), kg lb (weight), C F K (temperature)",
+	"convert.value":         "Value (or q to quit):",
+	"convert.from":          "From unit:",
+	"convert.to":            "To unit:",
+	"convert.unknown_unit":  "unknown unit %q",
+	"convert.incompatible":  "cannot convert %s (%s) to %s (%s)",
+	"dimension.length":      "length",
+	"dimension.weight":      "weight",
+	"dimension.temperature": "temperature",
+
+	"currency.list":     "Currencies:",
+	"currency.amount":   "Amount (or q to quit):",
+	"currency.from":     "From currency:",
+	"currency.to":       "To currency:",
+	"currency.unknown":  "unknown currency %q",
+	"currency.builtin":  "using built-in rates, live rates are unavailable:",
+	"currency.fetching": "fetching rates:",
+	"currency.no_rate":  "no rate for %s",
+
+	"dice.welcome":  `Dice roller. Type dice like 3d6+2, "help" for more or q to quit.`,
+	"dice.prompt":   "dice> ",
+	"dice.bad":      "%q is not dice notation like 3d6+2",
+	"dice.count":    "the number of dice must be from 1 to %d, not %d",
+	"dice.sides":    "the number of sides must be from 1 to %d, not %d",
+	"dice.modifier": "the number added must be from %d to %d",
+
+	"encode.welcome": `Encoder. Type "help" for the commands.`,
+	"encode.prompt":  "encode> ",
+	"encode.invalid": "invalid encoding",
+
+	"guess.welcome": "I'm thinking of a number between 1 and 100 (q to quit).",
+	"guess.prompt":  "Your guess:",
+	"guess.higher":  "higher",
+	"guess.lower":   "lower",
+	"guess.correct": "correct in %d tries",
+
+	"mathtoys.welcome":       `Math toys. Type "help" for the commands.`,
+	"mathtoys.prompt":        "math> ",
+	"mathtoys.how_many":      "How many? ",
+	"mathtoys.up_to":         "Up to? ",
+	"mathtoys.isprime_usage": "isprime needs a whole number from 0 to %d",
+	"mathtoys.prime":         "%d is prime",
+	"mathtoys.not_prime":     "%d is not prime",
+
+	"roman.welcome":    `Roman numerals. Type "help" for the commands.`,
+	"roman.prompt":     "roman> ",
+	"roman.number":     "Number? ",
+	"roman.range":      "only numbers from %d to %d can be written as Roman numerals",
+	"roman.bad":        "%q is not a Roman numeral",
+	"roman.malformed":  "%q is not a well-formed Roman numeral",
+	"roman.canonical":  "%q is not a well-formed Roman numeral; %d is written %s",
+	"roman.from_usage": "usage: from <numeral>",
+
+	"stats.welcome":  `Stats. Enter numbers, then a blank line or "." (help for help, q to quit).`,
+	"stats.skipping": "skipping %q, it is not a number",
+	"stats.count":    "count",
+	"stats.sum":      "sum",
+	"stats.mean":     "mean",
+	"stats.median":   "median",
+	"stats.min":      "min",
+	"stats.max":      "max",
+	"stats.stddev":   "stddev",
+
+	"stopwatch.welcome":     `Stopwatch. Type "help" for the commands.`,
+	"stopwatch.prompt":      "stopwatch> ",
+	"stopwatch.started":     "Started.",
+	"stopwatch.lap":         "Lap %d: %s",
+	"stopwatch.stopped":     "Stopped at %s",
+	"stopwatch.reset":       "Reset.",
+	"stopwatch.running":     "the stopwatch is already running",
+	"stopwatch.not_running": "the stopwatch is not running",
+
+	"todo.welcome":     `To-do list. Type "help" for the commands.`,
+	"todo.prompt":      "todo> ",
+	"todo.nothing":     "Nothing to do.",
+	"todo.task_number": "Task number: ",
+	"todo.add_usage":   "usage: add <text>",
+	"todo.no_tasks":    "there are no tasks",
+	"todo.no_task":     "no task %d",
+	"todo.invalid":     "%s is not a valid to-do list: %w",
+
+	"wordcount.welcome": `Word count. Enter some text, then "." on a line of its own (help for help, q to quit).`,
+	"wordcount.lines":   "lines",
+	"wordcount.words":   "words",
+	"wordcount.chars":   "chars",
+	"wordcount.bytes":   "bytes",
+	"wordcount.longest": "longest",
+}
@@ -0,0 +1,312 @@
+package main
+
+import "fmt"
+
+// spanish is the Spanish catalog, selected with -lang es or LANG=es_*.
+var spanish = map[string]string{
+	"error":   "Error:",
+	"warning": "Aviso:",
+
+	"menu.main":      "Inicio",
+	"menu.title":     "Elige una aplicación:",
+	"menu.exit":      "Salir",
+	"menu.back":      "Volver",
+	"menu.exited":    "Has salido",
+	"menu.exiting":   "Saliendo...",
+	"menu.selected":  "  %s: elegida %d vez/veces",
+	"menu.cancelled": "Cancelado",
+	"menu.continue":  "Pulsa Intro para continuar...",
+	"menu.enter":     "Intro",
+	"menu.last_used": "%s (la última usada)",
+
+	"summary.duration":     "La sesión ha durado %s.",
+	"summary.apps":         "Aplicaciones usadas:",
+	"summary.no_apps":      "No se ha usado ninguna aplicación.",
+	"summary.calculations": "Cálculos: %d",
+
+	"help.apps":     "Aplicaciones:",
+	"help.commands": "Órdenes:",
+	"help.open":     "abre la entrada con ese número",
+	"help.exit":     "salir",
+	"help.last":     "vuelve a abrir %s",
+	"help.back":     "vuelve al menú %s",
+	"help.help":     "muestra esta ayuda",
+	"help.version":  "muestra la versión",
+	"help.inside":   `Dentro de una aplicación, escribe "help" para ver cómo se usa.`,
+
+	"auth.enter":    "Introduce la contraseña:",
+	"auth.waiting":  "Esperando %v antes de comprobarla...",
+	"auth.wrong":    "Contraseña incorrecta, inténtalo de nuevo.",
+	"auth.none":     "Todavía no hay contraseña.",
+	"auth.choose":   "Elige una contraseña:",
+	"auth.confirm":  "Confirma la contraseña:",
+	"auth.mismatch": "Las contraseñas no coinciden.",
+	"auth.saved":    "Contraseña guardada.",
+	"auth.locked":   "Demasiados intentos, acceso bloqueado",
+	"auth.bad":      "contraseña incorrecta",
+
+	"password.length": "La contraseña debe tener al menos %d caracteres.",
+	"password.digit":  "La contraseña debe contener al menos un dígito.",
+	"password.upper":  "La contraseña debe contener al menos una letra mayúscula.",
+	"password.symbol": "La contraseña debe contener al menos un símbolo.",
+
+	"calc.welcome":         `Calculadora. Escribe "help" para ver la ayuda o "exit" para volver al menú.`,
+	"calc.expected_base":   "se esperaba as hex, as oct, as bin o as dec",
+	"calc.expected_mode":   "se esperaba mode big, float, deg o rad",
+	"calc.expected_format": "se esperaba format seguido de 0 a %d, auto, group on o group off",
+	"calc.fell_back":       "no es una expresión entera, se usa el modo float",
+	"calc.no_vars":         "Todavía no hay variables.",
+	"calc.no_history":      "Todavía no hay cálculos.",
+	"calc.no_result":       "no hay un resultado anterior",
+	"calc.no_continue":     "no hay un resultado anterior desde el que continuar",
+
+	"eval.divide_by_zero":  "no se puede dividir entre cero",
+	"eval.sqrt_negative":   "raíz cuadrada de un número negativo %v",
+	"eval.fact_whole":      "el factorial necesita un número entero >= 0, no %v",
+	"eval.fact_too_large":  "el factorial de %v es demasiado grande (el límite es %d)",
+	"eval.non_positive":    "%s de un número no positivo %v",
+	"eval.bad_char":        "carácter inesperado %q en la posición %d",
+	"eval.no_ans":          "ans: todavía no hay un resultado anterior",
+	"eval.undefined":       "variable no definida: %s",
+	"eval.negative_shift":  "desplazamiento negativo %v",
+	"eval.shift_too_large": "el desplazamiento %v es demasiado grande",
+	"eval.unknown_op":      "operador desconocido %q",
+	"eval.needs_whole":     "el operador %s necesita números enteros, no %v",
+	"eval.int_too_large":   "el operador %s necesita números enteros de hasta 2^53, no %v",
+	"eval.bad_number":      "número no válido %q en la posición %d",
+	"eval.unknown_func":    "función desconocida %q en la posición %d",
+	"eval.unexpected_end":  "la expresión termina antes de tiempo",
+	"eval.unexpected":      "%q inesperado en la posición %d",
+	"eval.missing_paren":   "falta el paréntesis de cierre del '(' de la posición %d",
+	"eval.empty":           "expresión vacía",
+	"eval.unmatched_paren": "')' sin pareja en la posición %d",
+	"eval.too_large":       "el resultado es demasiado grande",
+	"eval.not_real":        "el resultado no es un número real",
+	"eval.tan_undefined":   "la tangente de %v grados no está definida",
+	"eval.not_integer":     "no es una expresión entera",
+	"eval.pow_too_large":   "el resultado de %v ^ %v es demasiado grande",
+	"eval.reserved":        "no se puede asignar al nombre reservado %q",
+	"eval.internal":        "no se ha podido evaluar esta expresión (%v)",
+	"eval.no_vars":         "no se puede asignar a %q: aquí no hay variables",
+
+	"number.group":   ".",
+	"number.decimal": ",",
+
+	"export.done":  "%d filas escritas en %s",
+	"export.usage": "uso: export <ruta>",
+
+	"input.int_range": "escribe un número entero del %d al %d",
+	"input.too_many":  "demasiadas respuestas no válidas",
+
+	"undo.nothing": "no hay nada que deshacer",
+	"redo.nothing": "no hay nada que rehacer",
+
+	"main.reading_config":    "al leer la configuración:",
+	"main.disabled":          "%s está desactivada en %s",
+	"main.opening_log":       "al abrir DEMO_LOG:",
+	"main.empty_history":     "se empieza con el historial vacío:",
+	"main.saving_history":    "al guardar el historial:",
+	"main.saving_config":     "al guardar la configuración:",
+	"theme.unknown":          "tema desconocido %q (se esperaba %s), se usa %s",
+	"history.invalid":        "%s no es un historial válido: %w",
+	"config.unknown_app":     "disabled: aplicación desconocida %q",
+	"config.calculator_mode": `calculator_mode: se esperaba "float" o "big", no %q`,
+	"config.input_timeout":   "input_timeout: se esperaba un número de segundos >= 0, no %d",
+
+	"command.unknown":      "orden desconocida %q",
+	"command.did_you_mean": "orden desconocida %q, ¿querías decir %q?",
+
+	"input.bad_number": "número no válido %q",
+	"input.timeout":    "se ha agotado el tiempo de espera",
+
+	"convert.units":         "Unidades: m ft mi (longitud), kg lb (peso), C F K (temperatura)",
+	"convert.value":         "Valor (o q para salir):",
+	"convert.from":          "Unidad de origen:",
+	"convert.to":            "Unidad de destino:",
+	"convert.unknown_unit":  "unidad desconocida %q",
+	"convert.incompatible":  "no se puede convertir %s (%s) a %s (%s)",
+	"dimension.length":      "longitud",
+	"dimension.weight":      "peso",
+	"dimension.temperature": "temperatura",
+
+	"currency.list":     "Divisas:",
+	"currency.amount":   "Cantidad (o q para salir):",
+	"currency.from":     "Divisa de origen:",
+	"currency.to":       "Divisa de destino:",
+	"currency.unknown":  "divisa desconocida %q",
+	"currency.builtin":  "se usan los tipos de cambio incorporados, los actuales no están disponibles:",
+	"currency.fetching": "al obtener los tipos de cambio:",
+	"currency.no_rate":  "no hay tipo de cambio para %s",
+
+	"dice.welcome":  `Dados. Escribe una tirada como 3d6+2, "help" para saber más o q para salir.`,
+	"dice.prompt":   "dados> ",
+	"dice.bad":      "%q no es una tirada como 3d6+2",
+	"dice.count":    "el número de dados debe ir de 1 a %d, no %d",
+	"dice.sides":    "el número de caras debe ir de 1 a %d, no %d",
+	"dice.modifier": "el número sumado debe ir de %d a %d",
+
+	"encode.welcome": `Codificador. Escribe "help" para ver las órdenes.`,
+	"encode.prompt":  "codificar> ",
+	"encode.invalid": "codificación no válida",
+
+	"guess.welcome": "Estoy pensando en un número entre 1 y 100 (q para salir).",
+	"guess.prompt":  "¿Qué número es?",
+	"guess.higher":  "más alto",
+	"guess.lower":   "más bajo",
+	"guess.correct": "acertado en %d intentos",
+
+	"mathtoys.welcome":       `Juegos matemáticos. Escribe "help" para ver las órdenes.`,
+	"mathtoys.prompt":        "mates> ",
+	"mathtoys.how_many":      "¿Cuántos? ",
+	"mathtoys.up_to":         "¿Hasta cuál? ",
+	"mathtoys.isprime_usage": "isprime necesita un número entero de 0 a %d",
+	"mathtoys.prime":         "%d es primo",
+	"mathtoys.not_prime":     "%d no es primo",
+
+	"roman.welcome":    `Números romanos. Escribe "help" para ver las órdenes.`,
+	"roman.prompt":     "romanos> ",
+	"roman.number":     "¿Número? ",
+	"roman.range":      "solo los números del %d al %d se pueden escribir en números romanos",
+	"roman.bad":        "%q no es un número romano",
+	"roman.malformed":  "%q no es un número romano bien escrito",
+	"roman.canonical":  "%q no es un número romano bien escrito; %d se escribe %s",
+	"roman.from_usage": "uso: from <número romano>",
+
+	"stats.welcome":  `Estadísticas. Escribe números y después una línea en blanco o "." (help para la ayuda, q para salir).`,
+	"stats.skipping": "se omite %q, no es un número",
+	"stats.count":    "cantidad",
+	"stats.sum":      "suma",
+	"stats.mean":     "media",
+	"stats.median":   "mediana",
+	"stats.min":      "mínimo",
+	"stats.max":      "máximo",
+	"stats.stddev":   "desviación",
+
+	"stopwatch.welcome":     `Cronómetro. Escribe "help" para ver las órdenes.`,
+	"stopwatch.prompt":      "cronómetro> ",
+	"stopwatch.started":     "En marcha.",
+	"stopwatch.lap":         "Vuelta %d: %s",
+	"stopwatch.stopped":     "Parado en %s",
+	"stopwatch.reset":       "A cero.",
+	"stopwatch.running":     "el cronómetro ya está en marcha",
+	"stopwatch.not_running": "el cronómetro no está en marcha",
+
+	"todo.welcome":     `Lista de tareas. Escribe "help" para ver las órdenes.`,
+	"todo.prompt":      "tareas> ",
+	"todo.nothing":     "No hay nada que hacer.",
+	"todo.task_number": "Número de tarea: ",
+	"todo.add_usage":   "uso: add <texto>",
+	"todo.no_tasks":    "no hay tareas",
+	"todo.no_task":     "no hay ninguna tarea %d",
+	"todo.invalid":     "%s no es una lista de tareas válida: %w",
+
+	"wordcount.welcome": `Contador de palabras. Escribe un texto y después "." en una línea aparte (help para la ayuda, q para salir).`,
+	"wordcount.lines":   "líneas",
+	"wordcount.words":   "palabras",
+	"wordcount.chars":   "caracteres",
+	"wordcount.bytes":   "bytes",
+	"wordcount.longest": "más larga",
+
+	"group.Tools": "Herramientas",
+
+	"app.calculator.name":        "Calculadora",
+	"app.calculator.description": "evalúa expresiones, con variables, memoria y enteros exactos",
+	"app.convert.name":           "Conversor de unidades",
+	"app.convert.description":    "convierte longitudes, pesos y temperaturas",
+	"app.currency.name":          "Conversor de divisas",
+	"app.currency.description":   "convierte entre USD, EUR, GBP y JPY con tipos de cambio actuales",
+	"app.dice.name":              "Dados",
+	"app.dice.description":       "tira dados escritos como 3d6+2",
+	"app.encode.name":            "Codificador",
+	"app.encode.description":     "codifica y descodifica texto en base64 o hexadecimal",
+	"app.guess.name":             "Adivina el número",
+	"app.guess.description":      "encuentra el número entre 1 y 100 que ha elegido el ordenador",
+	"app.mathtoys.name":          "Juegos matemáticos",
+	"app.mathtoys.description":   "lista números de Fibonacci y primos, y comprueba si un número es primo",
+	"app.roman.name":             "Números romanos",
+	"app.roman.description":      "convierte números enteros a números romanos y al revés",
+	"app.stats.name":             "Estadísticas",
+	"app.stats.description":      "cantidad, suma, media, mediana, mínimo, máximo y desviación típica de números",
+	"app.stopwatch.name":         "Cronómetro",
+	"app.stopwatch.description":  "mide el tiempo, con vueltas",
+	"app.todo.name":              "Lista de tareas",
+	"app.todo.description":       "guarda una lista de tareas entre sesiones",
+	"app.wordcount.name":         "Contador de palabras",
+	"app.wordcount.description":  "cuenta las líneas, palabras, caracteres y bytes de un texto, como wc",
+
+	"app.calculator.usage": `Escribe una expresión como 2 + 3 * (4 - 1) para evaluarla.
+Empieza una línea con un operador, como en "+ 20" o "- 5", para seguir desde el último resultado.
+Operadores: + - * / ^ ! % & | xor << >>  (15% es 0,15, así que 200 * 15% es 30)
+Funciones: sqrt sin cos tan asin acos atan log ln exp fact
+Nombres: pi, e, ans (el resultado anterior), MR (la memoria) y las variables fijadas con x = 5
+Órdenes:
+  MS, M+, M-, MC       guarda, suma o resta el último resultado en la memoria, o la borra
+  vars                 lista las variables y sus valores
+  undo, redo           deshace la última asignación o cambio de memoria, o la rehace
+  history              lista los cálculos hechos
+  export <fichero>     escribe el historial en un fichero CSV
+  as hex|oct|bin|dec   elige la base en la que se muestran los resultados
+  mode big|float       aritmética entera exacta o coma flotante
+  mode deg|rad         ángulos en grados o radianes (el indicador muestra cuál)
+  format n|auto        muestra los resultados con n decimales, o con los necesarios
+  format group on|off  separa los miles en los resultados
+  format               muestra el formato actual
+  clear                olvida las líneas que recupera la flecha arriba
+  help                 muestra este texto
+  exit, q              vuelve al menú`,
+	"app.convert.usage": `Escribe un valor, luego su unidad y luego la unidad a la que convertirlo.
+Unidades: m ft mi (longitud), kg lb (peso), C F K (temperatura)
+Escribe q en cualquier momento para volver al menú.`,
+	"app.currency.usage": `Escribe una cantidad, luego su divisa y luego la divisa a la que convertirla.
+Divisas: USD EUR GBP JPY
+Los tipos de cambio vienen de api.frankfurter.app y se guardan 10 minutos. Si no
+se pueden obtener, se usan los incorporados.
+Escribe q en cualquier momento para volver al menú.`,
+	"app.dice.usage": fmt.Sprintf(`Escribe la tirada como NdM+K: N dados de M caras cada uno, más K.
+N y K son opcionales, así que d20 tira un dado y 2d6-1 resta 1 al total.
+N puede llegar a %d y M a %d. Escribe q para volver al menú.`, maxDice, maxSides),
+	"app.encode.usage": `Órdenes:
+  b64e <texto>  codifica el texto en base64
+  b64d <texto>  descodifica base64
+  hexe <texto>  codifica el texto en hexadecimal
+  hexd <texto>  descodifica hexadecimal
+  help          muestra este texto
+  q             vuelve al menú
+Todo lo que va tras el primer espacio es el texto, espacios incluidos.`,
+	"app.guess.usage": `Escribe un número entero del 1 al 100. Se te dice si el número es
+más alto o más bajo, hasta que aciertes. Escribe q para rendirte.`,
+	"app.mathtoys.usage": fmt.Sprintf(`Órdenes:
+  fib <n>       los n primeros números de Fibonacci (n hasta %d)
+  prime <n>     los primos hasta n (n hasta %d)
+  isprime <n>   si n es primo (n hasta %d)
+  help          muestra este texto
+  q             vuelve al menú`, maxFibCount, maxSieve, uint64(1<<64-1)),
+	"app.roman.usage": fmt.Sprintf(`Órdenes:
+  to <n>      n en números romanos (n del %d al %d)
+  from <s>    el número que representa el número romano s
+  help        muestra este texto
+  q           vuelve al menú`, minRoman, maxRoman),
+	"app.stats.usage": `Escribe números separados por espacios o uno por línea, y después una línea
+en blanco o "." para ver sus estadísticas. Lo que no sea un número se omite.
+Escribe q para volver al menú.`,
+	"app.stopwatch.usage": `Órdenes:
+  start   empieza o reanuda la medición
+  lap     muestra el tiempo hasta ahora y lo guarda como vuelta
+  stop    para y muestra el total
+  reset   borra el tiempo y las vueltas
+  help    muestra este texto
+  q       vuelve al menú`,
+	"app.todo.usage": `Órdenes:
+  add <texto>  añade una tarea
+  done [n]     marca la tarea n como hecha, preguntando n si falta
+  rm [n]       quita la tarea n, preguntando n si falta
+  list         muestra las tareas
+  undo, redo   deshace el último cambio, o lo rehace
+  export <f>   escribe las tareas en el fichero CSV f
+  help         muestra este texto
+  q            vuelve al menú`,
+	"app.wordcount.usage": `Escribe o pega un texto y después una línea con solo "." para contarlo.
+Cada línea cuenta con su fin de línea, como en wc. La línea más larga se
+mide en caracteres sin su fin. Escribe help o q como primera línea de un
+texto para ver esta ayuda o volver al menú.`,
+}
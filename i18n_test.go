@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// useLocale selects the locale name for as long as t runs.
+func useLocale(t *testing.T, name string) {
+	t.Helper()
+	saved := locale
+	t.Cleanup(func() { locale = saved })
+	if !setLocale(name) {
+		t.Fatalf("setLocale(%q) = false", name)
+	}
+}
+
+func TestSetLocale(t *testing.T) {
+	saved := locale
+	t.Cleanup(func() { locale = saved })
+	tests := []struct {
+		name string
+		ok   bool
+		want string
+	}{
+		{"es", true, "es"},
+		{"es_ES.UTF-8", true, "es"},
+		{"ES_mx", true, "es"},
+		{"en_GB.UTF-8", true, "en"},
+		{"fr_FR.UTF-8", false, "en"},
+		{"C", false, "en"},
+		{"", false, "en"},
+	}
+	for _, tt := range tests {
+		locale = "es"
+		if ok := setLocale(tt.name); ok != tt.ok || locale != tt.want {
+			t.Errorf("setLocale(%q) = %v with locale %q, want %v with %q", tt.name, ok, locale, tt.ok, tt.want)
+		}
+	}
+}
+
+func TestLocaleChangesMenu(t *testing.T) {
+	english := runMenu(t, defaultConfig, menuInput("?\n\n{Exit}\n"))
+	checkInOrder(t, english, "Main", "Choose app:", "[1] Tools >", "Calculator", "Exit", "Exited")
+
+	useLocale(t, "es_ES.UTF-8")
+	spanish := runMenu(t, defaultConfig, menuInput("?\n\n{Exit}\n"))
+	checkInOrder(t, spanish, "Inicio", "Elige una aplicación:", "[1] Herramientas >", "Calculadora", "Salir", "Has salido")
+	for _, s := range []string{"Choose app:", "Exited", "Calculator"} {
+		if strings.Contains(spanish, s) {
+			t.Errorf("the Spanish menu still says %q:\n%s", s, spanish)
+		}
+	}
+}
+
+func TestCatalogs(t *testing.T) {
+	for key := range spanish {
+		if _, ok := english[key]; !ok && !strings.HasPrefix(key, "app.") && !strings.HasPrefix(key, "group.") {
+			t.Errorf("Spanish has %q, which English lacks", key)
+		}
+	}
+	verbs := regexp.MustCompile(`%[-+# 0*]*[a-zA-Z]`)
+	for key, en := range english {
+		es, ok := spanish[key]
+		if !ok {
+			t.Errorf("Spanish has no %q", key)
+			continue
+		}
+		if got, want := verbs.FindAllString(es, -1), verbs.FindAllString(en, -1); !slices.Equal(got, want) {
+			t.Errorf("%s: Spanish formats %q, English %q", key, got, want)
+		}
+	}
+	for _, app := range apps {
+		for _, key := range []string{"app." + app.ID + ".name", "app." + app.ID + ".description", "app." + app.ID + ".usage"} {
+			if _, ok := spanish[key]; !ok {
+				t.Errorf("Spanish has no %s", key)
+			}
+		}
+	}
+	useLocale(t, "es")
+	if got := T("no.such.key"); got != "no.such.key" {
+		t.Errorf("T of an unknown key = %q, want the key", got)
+	}
+}
+
+func TestLocaleChangesApps(t *testing.T) {
+	useLocale(t, "es")
+	var out bytes.Buffer
+	if err := playGuess(strings.NewReader("101\nhelp\nq\n"), &out, slog.New(discardHandler{}), 1); err != nil {
+		t.Fatal(err)
+	}
+	checkInOrder(t, out.String(), "Estoy pensando en un número", "¿Qué número es?",
+		"Error: escribe un número entero del 1 al 100", "Escribe un número entero del 1 al 100.")
+
+	out.Reset()
+	printStats(&out, []float64{1, 2, 3})
+	// The values line up after the longest label, however long it is.
+	checkInOrder(t, out.String(), "cantidad    3\n", "suma        6\n", "desviación  0.8164965809\n")
+
+	if _, err := convert(1, "kg", "ft"); err == nil || err.Error() != "no se puede convertir kg (peso) a ft (longitud)" {
+		t.Errorf("convert(kg, ft) in Spanish: %v", err)
+	}
+	if got, want := unknownCommand("hlp", []string{"help"}).Error(), `orden desconocida "hlp", ¿querías decir "help"?`; got != want {
+		t.Errorf("unknownCommand in Spanish = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// readLine reads one line from r and returns it without the line ending.
+// A *bufio.Reader is read from directly; any other reader is read a byte at a
+// time so nothing past the newline is consumed.
+func readLine(r io.Reader) (string, error) {
+	if br, ok := r.(*bufio.Reader); ok {
+		line, err := br.ReadString('\n')
+		if err == io.EOF && line != "" {
+			err = nil
+		}
+		return strings.TrimRight(line, "\r\n"), err
+	}
+
+	var sb strings.Builder
+	b := make([]byte, 1)
+	for {
+		n, err := r.Read(b)
+		if n == 1 {
+			if b[0] == '\n' {
+				break
+			}
+			sb.WriteByte(b[0])
+		}
+		if err != nil {
+			if err == io.EOF && sb.Len() > 0 {
+				break
+			}
+			return sb.String(), err
+		}
+	}
+	return strings.TrimRight(sb.String(), "\r"), nil
+}
+
+// maxIntAttempts is how many answers readInt reads before giving up.
+const maxIntAttempts = 3
+
+// errTooManyAttempts is returned by readInt after maxIntAttempts bad
+// answers.
+var errTooManyAttempts = newError("input.too_many")
+
+// parseInt parses s as a whole number from min to max.
+func parseInt(s string, min, max int) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < min || n > max {
+		return 0, newError("input.int_range", min, max)
+	}
+	return n, nil
+}
+
+// readInt prints prompt on w and reads a whole number from min to max from
+// r. Bad answers are reported and asked again, up to maxIntAttempts
+// answers in all. Read errors, io.EOF included, are returned unchanged.
+func readInt(r io.Reader, w io.Writer, prompt string, min, max int) (int, error) {
+	for range maxIntAttempts {
+		fmt.Fprint(w, prompt)
+		line, err := readLine(r)
+		if err != nil {
+			return 0, err
+		}
+		n, err := parseInt(line, min, max)
+		if err == nil {
+			return n, nil
+		}
+		printError(w, err)
+	}
+	return 0, errTooManyAttempts
+}
+
+// intArg returns the whole number from min to max given as a command's
+// argument, or asks for one with prompt when arg is empty. A bad number is
+// reported on w and gives ok == false; err is only set by read errors,
+// io.EOF included.
+func intArg(r io.Reader, w io.Writer, arg, prompt string, min, max int) (n int, ok bool, err error) {
+	if arg == "" {
+		n, err = readInt(r, w, prompt, min, max)
+		if err == errTooManyAttempts {
+			printError(w, err)
+			return 0, false, nil
+		}
+		return n, err == nil, err
+	}
+	n, err = parseInt(arg, min, max)
+	if err != nil {
+		printError(w, err)
+		return 0, false, nil
+	}
+	return n, true, nil
+}
+
+// errInterrupted is returned by reads abandoned because of Ctrl+C.
+var errInterrupted = errors.New("interrupted")
+
+// errTimeout is returned by reads that saw no input for the reader's
+// timeout.
+var errTimeout = newError("input.timeout")
+
+type chunk struct {
+	data []byte
+	err  error
+}
+
+// interruptReader reads from src in a background goroutine so that a
+// blocked Read can give up with errInterrupted as soon as Ctrl+C is
+// pressed, or with errTimeout once there has been no input for a while.
+// Only one goroutine ever reads src and whatever it reads after a Read
+// gives up is kept for the next Read, so no input is lost.
+//
+// It is also the only receiver of the interrupt channel, so that no signal
+// goes to a goroutine that is not waiting for it: each signal cancels the
+// innermost context returned by Context, and Read gives up once that
+// context is done. Whatever is running then stops, reading or not.
+type interruptReader struct {
+	timeout time.Duration // 0 waits forever
+	chunks  chan chunk
+	buf     []byte
+	err     error
+
+	mu      sync.Mutex
+	current *interruptContext // innermost context from Context, or nil
+}
+
+// interruptContext is a context handed out by interruptReader.Context, with
+// the one it replaced.
+type interruptContext struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	outer  *interruptContext
+}
+
+func newInterruptReader(src io.Reader, interrupt <-chan os.Signal) *interruptReader {
+	r := &interruptReader{chunks: make(chan chunk)}
+	go func() {
+		for {
+			buf := make([]byte, 4096)
+			n, err := src.Read(buf)
+			r.chunks <- chunk{buf[:n], err}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	go func() {
+		for range interrupt {
+			r.mu.Lock()
+			if r.current != nil {
+				r.current.cancel()
+			}
+			r.mu.Unlock()
+		}
+	}()
+	return r
+}
+
+// Context returns a copy of parent that the next interrupt cancels, and
+// that Read watches, until the returned function is called. Calls nest: the
+// function makes the context that was current before this call current
+// again, so Ctrl+C in an app stops only the app. On a nil *interruptReader,
+// as in scripts, nothing interrupts the context.
+func (r *interruptReader) Context(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	if r == nil {
+		return ctx, cancel
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := &interruptContext{ctx, cancel, r.current}
+	r.current = c
+	return ctx, func() {
+		cancel()
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.current == c {
+			r.current = c.outer
+		}
+	}
+}
+
+// done returns the channel that is closed when the current context is
+// done, or nil if there is none.
+func (r *interruptReader) done() <-chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.current == nil {
+		return nil
+	}
+	return r.current.ctx.Done()
+}
+
+// SetTimeout makes each Read give up with errTimeout after d without
+// input. Zero turns the timeout off.
+func (r *interruptReader) SetTimeout(d time.Duration) {
+	r.timeout = d
+}
+
+func (r *interruptReader) Read(p []byte) (int, error) {
+	var timeout <-chan time.Time
+	if r.timeout > 0 && len(r.buf) == 0 && r.err == nil {
+		t := time.NewTimer(r.timeout)
+		defer t.Stop()
+		timeout = t.C
+	}
+	done := r.done()
+	for len(r.buf) == 0 && r.err == nil {
+		select {
+		case c := <-r.chunks:
+			r.buf, r.err = c.data, c.err
+		case <-done:
+			return 0, errInterrupted
+		case <-timeout:
+			return 0, errTimeout
+		}
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	if n == 0 {
+		return 0, r.err
+	}
+	return n, nil
+}
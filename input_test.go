@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReadInt(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   int
+		errors int // bad answers reported before it
+	}{
+		{"valid", "7\n", 7, 0},
+		{"spaces", "  7 \n", 7, 0},
+		{"lowest", "1\n", 1, 0},
+		{"highest", "10\n", 10, 0},
+		{"out of range", "0\n11\n5\n", 5, 2},
+		{"not a number", "seven\n7.5\n6\n", 6, 2},
+		{"blank", "\n6\n", 6, 1},
+		{"no newline at the end", "4", 4, 0},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		got, err := readInt(strings.NewReader(tt.input), &out, "n? ", 1, 10)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: readInt = %d, %v; want %d", tt.name, got, err, tt.want)
+		}
+		if n := strings.Count(out.String(), "Error: please enter a whole number from 1 to 10"); n != tt.errors {
+			t.Errorf("%s: %d errors reported, want %d:\n%s", tt.name, n, tt.errors, out.String())
+		}
+		if n := strings.Count(out.String(), "n? "); n != tt.errors+1 {
+			t.Errorf("%s: asked %d times, want %d", tt.name, n, tt.errors+1)
+		}
+	}
+}
+
+func TestReadIntGivesUp(t *testing.T) {
+	var out bytes.Buffer
+	input := strings.Repeat("x\n", maxIntAttempts) + "5\n"
+	if _, err := readInt(strings.NewReader(input), &out, "n? ", 1, 10); err != errTooManyAttempts {
+		t.Errorf("readInt after %d bad answers: err = %v, want errTooManyAttempts", maxIntAttempts, err)
+	}
+	if n := strings.Count(out.String(), "n? "); n != maxIntAttempts {
+		t.Errorf("asked %d times, want %d", n, maxIntAttempts)
+	}
+
+	// The last chance still counts.
+	input = strings.Repeat("x\n", maxIntAttempts-1) + "5\n"
+	if n, err := readInt(strings.NewReader(input), io.Discard, "", 1, 10); err != nil || n != 5 {
+		t.Errorf("readInt with a good last answer = %d, %v; want 5", n, err)
+	}
+}
+
+func TestReadIntEOF(t *testing.T) {
+	for _, input := range []string{"", "x\n", "0\n99\n"} {
+		if _, err := readInt(strings.NewReader(input), io.Discard, "", 1, 10); err != io.EOF {
+			t.Errorf("readInt(%q) err = %v, want io.EOF", input, err)
+		}
+	}
+}
+
+func TestIntArg(t *testing.T) {
+	var out bytes.Buffer
+	if n, ok, err := intArg(strings.NewReader(""), &out, "3", "n? ", 1, 5); n != 3 || !ok || err != nil {
+		t.Errorf("intArg with 3 = %d, %v, %v", n, ok, err)
+	}
+	if _, ok, err := intArg(strings.NewReader(""), &out, "9", "n? ", 1, 5); ok || err != nil {
+		t.Errorf("intArg with 9 = %v, %v; want a reported error", ok, err)
+	}
+	if n, ok, err := intArg(strings.NewReader("x\n2\n"), &out, "", "n? ", 1, 5); n != 2 || !ok || err != nil {
+		t.Errorf("intArg asking = %d, %v, %v", n, ok, err)
+	}
+	out.Reset()
+	input := strings.Repeat("x\n", maxIntAttempts)
+	if _, ok, err := intArg(strings.NewReader(input), &out, "", "n? ", 1, 5); ok || err != nil {
+		t.Errorf("intArg after too many bad answers = %v, %v; want a reported error", ok, err)
+	}
+	if !strings.Contains(out.String(), errTooManyAttempts.Error()) {
+		t.Errorf("too many attempts not reported:\n%s", out.String())
+	}
+}
+
+// waitDone fails t unless ctx is done within a second.
+func waitDone(t *testing.T, ctx context.Context, what string) {
+	t.Helper()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("%s was not cancelled", what)
+	}
+}
+
+func TestInterruptReader(t *testing.T) {
+	pr, pw := io.Pipe()
+	interrupt := make(chan os.Signal, 1)
+	r := newInterruptReader(pr, interrupt)
+	ctx, cancel := r.Context(context.Background())
+	defer cancel()
+
+	// Ctrl+C while a Read is blocked makes it give up.
+	errc := make(chan error, 1)
+	go func() {
+		_, err := r.Read(make([]byte, 10))
+		errc <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	interrupt <- os.Interrupt
+	select {
+	case err := <-errc:
+		if err != errInterrupted {
+			t.Errorf("Read after Ctrl+C: err = %v, want errInterrupted", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Read did not give up after Ctrl+C")
+	}
+	waitDone(t, ctx, "the context")
+
+	// Input that arrives after that is kept for a new context's Reads.
+	go io.WriteString(pw, "hello\n")
+	ctx2, cancel2 := r.Context(context.Background())
+	defer cancel2()
+	if line, err := readLine(r); err != nil || line != "hello" {
+		t.Errorf("readLine = %q, %v; want hello", line, err)
+	}
+	if ctx2.Err() != nil {
+		t.Error("the new context was cancelled by the old Ctrl+C")
+	}
+}
+
+func TestInterruptReaderNested(t *testing.T) {
+	interrupt := make(chan os.Signal, 1)
+	r := newInterruptReader(strings.NewReader(""), interrupt)
+	outer, cancelOuter := r.Context(context.Background())
+	defer cancelOuter()
+
+	// Ctrl+C while an app is busy, not reading, stops only the app.
+	inner, cancelInner := r.Context(outer)
+	interrupt <- os.Interrupt
+	waitDone(t, inner, "the app's context")
+	if outer.Err() != nil {
+		t.Error("Ctrl+C in an app cancelled the menu's context")
+	}
+	cancelInner()
+
+	// Back at the menu, Ctrl+C cancels the menu's context.
+	interrupt <- os.Interrupt
+	waitDone(t, outer, "the menu's context")
+
+	// A nil reader, as in scripts, gives plain contexts.
+	var none *interruptReader
+	ctx, cancel := none.Context(context.Background())
+	if ctx.Err() != nil {
+		t.Error("a nil reader's context starts cancelled")
+	}
+	cancel()
+}
+
+func TestRunMenuInterruptsApp(t *testing.T) {
+	t.Setenv("DEMO_NO_CLEAR", "1")
+	t.Setenv("HOME", t.TempDir())
+	pr, pw := io.Pipe()
+	interrupt := make(chan os.Signal, 1)
+	src := newInterruptReader(pr, interrupt)
+	ctx, cancel := src.Context(context.Background())
+	defer cancel()
+
+	var out syncBuffer
+	done := make(chan struct{})
+	go func() {
+		env := &Env{In: src, Out: &out, Log: slog.New(discardHandler{}), Config: defaultConfig, Session: newSession(time.Now()), Quiet: true}
+		run(ctx, env, src)
+		close(done)
+	}()
+
+	// Ctrl+C at the calculator prompt goes back to the menu, which is
+	// still there to exit from.
+	io.WriteString(pw, menuInput("{Tools}\n1\n2 + 2\n"))
+	waitFor(t, &out, "4\n")
+	interrupt <- os.Interrupt
+	waitFor(t, &out, "Cancelled")
+	io.WriteString(pw, menuInput("\nb\n{Exit}\n"))
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("the menu did not exit:\n%s", out.String())
+	}
+	checkInOrder(t, out.String(), "Main > Tools > Calculator", "4\n", "Cancelled", "Main > Tools", "Exited")
+}
+
+// syncBuffer is a bytes.Buffer that a test can read while another
+// goroutine writes to it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// waitFor fails t unless b contains s within a second.
+func waitFor(t *testing.T, b *syncBuffer, s string) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); !strings.Contains(b.String(), s); {
+		if time.Now().After(deadline) {
+			t.Fatalf("output is missing %q:\n%s", s, b.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
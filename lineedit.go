@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// lineHistory is a fixed size ring buffer of entered lines. It implements
+// term.History, which is what makes the up arrow recall them.
+type lineHistory struct {
+	lines []string
+	start int // index of the oldest line
+	n     int // number of lines held
+}
+
+func newLineHistory(size int) *lineHistory {
+	return &lineHistory{lines: make([]string, size)}
+}
+
+// Add records line, overwriting the oldest line once the buffer is full.
+func (h *lineHistory) Add(line string) {
+	if line == "" {
+		return
+	}
+	if h.n < len(h.lines) {
+		h.lines[(h.start+h.n)%len(h.lines)] = line
+		h.n++
+		return
+	}
+	h.lines[h.start] = line
+	h.start = (h.start + 1) % len(h.lines)
+}
+
+func (h *lineHistory) Len() int {
+	return h.n
+}
+
+// At returns the idx-th most recent line; 0 is the newest.
+func (h *lineHistory) At(idx int) string {
+	if idx < 0 || idx >= h.n {
+		panic(fmt.Sprintf("lineHistory: index %d out of range [0,%d)", idx, h.n))
+	}
+	return h.lines[(h.start+h.n-1-idx)%len(h.lines)]
+}
+
+// Clear forgets every line.
+func (h *lineHistory) Clear() {
+	h.start, h.n = 0, 0
+}
+
+// lineEditor reads a line at a time after showing a prompt. Output meant
+// to appear between prompts is written to it too.
+type lineEditor interface {
+	io.Writer
+	ReadLine() (string, error)
+	SetPrompt(prompt string)
+	ClearHistory()
+}
+
+// plainEditor is the lineEditor for pipes, files and tests.
+type plainEditor struct {
+	r      io.Reader
+	w      io.Writer
+	prompt string
+}
+
+func (e *plainEditor) Write(p []byte) (int, error) {
+	return e.w.Write(p)
+}
+
+func (e *plainEditor) ReadLine() (string, error) {
+	fmt.Fprint(e.w, e.prompt)
+	return readLine(e.r)
+}
+
+func (e *plainEditor) SetPrompt(prompt string) {
+	e.prompt = prompt
+}
+
+func (*plainEditor) ClearHistory() {}
+
+// termEditor is the lineEditor for an interactive terminal, with arrow key
+// editing and recall. The terminal is only in raw mode while a line is
+// being read, so Ctrl+C between prompts is still a signal.
+type termEditor struct {
+	*term.Terminal
+	history *lineHistory
+	fd      int
+}
+
+func (e termEditor) ReadLine() (string, error) {
+	state, err := term.MakeRaw(e.fd)
+	if err != nil {
+		return "", err
+	}
+	defer term.Restore(e.fd, state)
+	return e.Terminal.ReadLine()
+}
+
+func (e termEditor) ClearHistory() {
+	e.history.Clear()
+}
+
+// newLineEditor returns a termEditor when stdin and w are both terminals
+// and a plainEditor otherwise.
+func newLineEditor(r io.Reader, w io.Writer, prompt string) lineEditor {
+	plain := &plainEditor{r, w, prompt}
+	out, ok := w.(*os.File)
+	stdin := int(os.Stdin.Fd())
+	if !ok || !term.IsTerminal(stdin) || !term.IsTerminal(int(out.Fd())) {
+		return plain
+	}
+
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{r, w}, prompt)
+	if width, height, err := term.GetSize(int(out.Fd())); err == nil && width > 0 {
+		t.SetSize(width, height)
+	}
+	history := newLineHistory(100)
+	t.History = history
+	return termEditor{t, history, stdin}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// lines returns what h holds, newest first.
+func lines(h *lineHistory) []string {
+	var got []string
+	for i := range h.Len() {
+		got = append(got, h.At(i))
+	}
+	return got
+}
+
+func TestLineHistory(t *testing.T) {
+	h := newLineHistory(3)
+	if h.Len() != 0 {
+		t.Fatalf("a new history holds %d lines", h.Len())
+	}
+	h.Add("1 + 1")
+	h.Add("")
+	h.Add("2 * 3")
+	if got, want := lines(h), []string{"2 * 3", "1 + 1"}; !slices.Equal(got, want) {
+		t.Errorf("history = %q, want %q newest first, without the empty line", got, want)
+	}
+
+	// Past its size the oldest lines are overwritten, again and again.
+	for _, line := range []string{"a", "b", "c", "d", "e"} {
+		h.Add(line)
+	}
+	if got, want := lines(h), []string{"e", "d", "c"}; !slices.Equal(got, want) {
+		t.Errorf("history after wrapping around = %q, want %q", got, want)
+	}
+
+	h.Clear()
+	if h.Len() != 0 {
+		t.Errorf("Clear left %d lines", h.Len())
+	}
+	h.Add("x")
+	if got, want := lines(h), []string{"x"}; !slices.Equal(got, want) {
+		t.Errorf("history after Clear and Add = %q, want %q", got, want)
+	}
+}
+
+func TestLineHistoryAtOutOfRange(t *testing.T) {
+	h := newLineHistory(2)
+	h.Add("only")
+	for _, idx := range []int{-1, 1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("At(%d) with one line did not panic", idx)
+				}
+			}()
+			h.At(idx)
+		}()
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+)
+
+// newLogger returns a logger that appends a JSON line per action to the
+// file named by DEMO_LOG, and a function that closes it. Without DEMO_LOG
+// the logger discards everything before any record is built.
+func newLogger() (*slog.Logger, func() error, error) {
+	path := os.Getenv("DEMO_LOG")
+	if path == "" {
+		return slog.New(discardHandler{}), func() error { return nil }, nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, nil, err
+	}
+	return slog.New(slog.NewJSONHandler(f, nil)), f.Close, nil
+}
+
+// discardHandler is a slog.Handler that is never enabled.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogCalculations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "demo.log")
+	t.Setenv("DEMO_LOG", path)
+	log, closeLog, err := newLogger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	env := &Env{Out: &out, Log: log, Config: defaultConfig}
+	ed := &plainEditor{strings.NewReader("2 + 3\n1 / 0\nq\n"), &out, "> "}
+	if err := calculatorREPL(context.Background(), ed, NewCalculator(), env); err != nil {
+		t.Fatal(err)
+	}
+	if err := closeLog(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var records []map[string]any
+	for s := bufio.NewScanner(f); s.Scan(); {
+		var r map[string]any
+		if err := json.Unmarshal(s.Bytes(), &r); err != nil {
+			t.Fatalf("log line %q is not JSON: %v", s.Text(), err)
+		}
+		records = append(records, r)
+	}
+	if len(records) != 2 {
+		t.Fatalf("logged %d records, want 2: %v", len(records), records)
+	}
+	want := []map[string]any{
+		{"level": "INFO", "msg": "evaluated", "expr": "2 + 3", "result": "5"},
+		{"level": "INFO", "msg": "evaluated", "expr": "1 / 0", "error": "cannot divide by zero"},
+	}
+	for i, w := range want {
+		for k, v := range w {
+			if records[i][k] != v {
+				t.Errorf("record %d: %s = %v, want %v", i, k, records[i][k], v)
+			}
+		}
+		if _, ok := records[i]["time"]; !ok {
+			t.Errorf("record %d has no time", i)
+		}
+	}
+}
+
+func TestLogDiscardedByDefault(t *testing.T) {
+	t.Setenv("DEMO_LOG", "")
+	log, closeLog, err := newLogger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeLog()
+	if log.Enabled(context.Background(), slog.LevelError) {
+		t.Error("the logger is enabled without DEMO_LOG")
+	}
+}
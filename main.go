@@ -1,48 +1,444 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
 )
 
-func checkLength() bool {
-	var password string
-	fmt.Println("Enter password:")
-	fmt.Scanln(&password)
-	return len(password) >= 5
+// exit ends session s with the goodbye message for err, which is nil after
+// Exit, and then the summary of s unless quiet is set.
+func exit(w io.Writer, err error, s *Session, quiet bool) {
+	s.End = time.Now()
+	if errors.Is(err, errInterrupted) {
+		fmt.Fprintln(w, "\n"+T("menu.exiting"))
+	} else {
+		fmt.Fprintln(w, T("menu.exited"))
+	}
+	if !quiet {
+		fmt.Fprintln(w, summarize(*s))
+	}
 }
 
-// Dummy functions to simulate options
-func optionA() {
-	fmt.Println("Option A selected")
-}
+// Choices readChoice returns for input other than a menu number.
+const (
+	backChoice    = 0  // "b" or "0": up one menu
+	helpChoice    = -1 // "?" or "help"
+	versionChoice = -2 // "version"
+	lastChoice    = -3 // a blank line: the last app used again
+)
 
-func optionB() {
-	fmt.Println("Option B selected")
+// readChoice reads menu choices from r until one is a number from 1 to max,
+// a blank line, going back or asking for help. Input timeouts are skipped,
+// since the menu is where a timeout leads, and anything else is reported
+// on w.
+func readChoice(r io.Reader, w io.Writer, max int) (int, error) {
+	for {
+		// Read the whole line so nothing is left behind for the next prompt.
+		line, err := readLine(r)
+		if errors.Is(err, errTimeout) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		line = strings.TrimSpace(line)
+		switch line {
+		case "":
+			return lastChoice, nil
+		case "b", "0":
+			return backChoice, nil
+		case "?", "help":
+			return helpChoice, nil
+		case "version":
+			return versionChoice, nil
+		}
+		x, err := parseInt(line, 1, max)
+		if err != nil {
+			printError(w, err)
+			continue
+		}
+		return x, nil
+	}
 }
 
-func main() {
+// run shows the menu of apps enabled in env.Config and reads choices until
+// the user exits. Picking a group opens its menu and "b" goes back up, and
+// Enter on the main menu launches the last app used again. An app that
+// times out waiting for input returns straight to the main menu, and
+// Ctrl+C while one runs cancels its context, with src.Context, and returns
+// to the menu too; src is nil when nothing can interrupt, as in scripts.
+// Each app launched is recorded in env.Log and env.Session.
+func run(ctx context.Context, env *Env, src *interruptReader) {
+	// Share one buffered reader so the apps and the menu read from the
+	// same input without losing what the other has buffered.
+	in, w := bufio.NewReader(env.In), env.Out
+	env = &Env{In: in, Out: w, Log: env.Log, Config: env.Config, Theme: env.Theme, Scripted: env.Scripted, Session: env.Session, Quiet: env.Quiet}
+	root := buildMenu(env.Config.enabledApps())
+	stack := menuStack{root}
+	session := env.Session
+	if _, app := root.find(env.Config.LastApp); app != nil {
+		session.LastApp = app.ID
+	}
+	title := env.Config.Title
+	if title == "" {
+		title = T("menu.title")
+	}
+
+	// launch runs app, which is listed in the menu on top of path.
+	launch := func(path menuStack, app *App) error {
+		session.launched(app.ID)
+		env.Log.Info("launched", "app", app.ID)
+		if env.Config.RememberLastApp && env.Config.LastApp != app.ID {
+			env.Config.LastApp = app.ID
+			configPath, err := dataPath("config.json")
+			if err == nil {
+				err = SaveConfig(configPath, env.Config)
+			}
+			if err != nil {
+				printError(w, fmt.Errorf("%s %w", T("main.saving_config"), err))
+			}
+		}
+		fmt.Fprintln(w, path.breadcrumb()+" > "+app.localName())
+		ctx, cancel := src.Context(ctx)
+		defer cancel()
+		return app.Run(ctx, env)
+	}
+
 	for {
-		var x int
-		fmt.Print("\033[H\033[2J")
-		fmt.Println("Choose app: ")
-		fmt.Println("[1] Calculator")
-		fmt.Println("[2] ???")
-		fmt.Println("[3] Exit")
-		fmt.Scan(&x)
-
-		switch x {
-		case 1:
-			optionA()
-		case 2:
-			optionB()
-		case 3:
-			fmt.Println("Exited")
+		menu := stack.top()
+		exitChoice := len(menu.Entries) + 1
+		if !env.Scripted {
+			clearScreen(w)
+		}
+		_, lastApp := root.find(session.LastApp)
+		printMenu(w, env.Theme, title, stack, lastApp)
+		last := len(menu.Entries)
+		if stack.atRoot() {
+			last = exitChoice
+		}
+
+		x, err := readChoice(in, w, last)
+		if err != nil {
+			// Input ended (Ctrl+D or the end of piped input) or Ctrl+C.
+			exit(w, err, session, env.Quiet)
+			return
+		}
+
+		switch {
+		case x == backChoice:
+			stack.pop()
+			continue
+		case x == helpChoice:
+			printHelp(w, stack, lastApp)
+		case x == versionChoice:
+			fmt.Fprintln(w, versionString())
+		case x == lastChoice:
+			if !stack.atRoot() || lastApp == nil {
+				continue // nothing to launch, so just redraw
+			}
+			path, _ := root.find(lastApp.ID)
+			err = launch(path, lastApp)
+		case x >= 1 && x <= len(menu.Entries):
+			e := menu.Entries[x-1]
+			if e.Menu != nil {
+				stack.push(e.Menu)
+				continue
+			}
+			err = launch(stack, e.App)
+		case x == exitChoice:
+			exit(w, nil, session, env.Quiet)
 			return // return instead of break
-		default:
-			fmt.Println("Not an option")
 		}
+		if errors.Is(err, errInterrupted) || errors.Is(err, context.Canceled) {
+			// Ctrl+C in an app only stops the app.
+			fmt.Fprintln(w, "\n"+T("menu.cancelled"))
+			err = nil
+		}
+		if errors.Is(err, errTimeout) {
+			// Whoever left the app behind is not coming back to this
+			// submenu either.
+			env.Log.Info("timed out")
+			stack.reset()
+			continue
+		}
+		if err != nil {
+			printError(w, err)
+		}
+
+		if env.Scripted {
+			continue // nobody needs time to read
+		}
+		fmt.Fprintln(w, T("menu.continue"))
+		if _, err := readLine(in); err != nil && !errors.Is(err, errTimeout) {
+			exit(w, err, session, env.Quiet)
+			return
+		}
+	}
+}
+
+// printMenu draws the menu on top of stack under the breadcrumb and title.
+// The main menu ends with Exit, then last if it is not nil, and the others
+// with Back.
+func printMenu(w io.Writer, t Theme, title string, stack menuStack, last *App) {
+	menu := stack.top()
+	fmt.Fprintln(w, stack.breadcrumb())
+	fmt.Fprintln(w, t.title(title))
+	for i, e := range menu.Entries {
+		name := e.Name()
+		if e.Menu != nil {
+			name += " >"
+		}
+		fmt.Fprintf(w, "%s %s\n", t.number(fmt.Sprintf("[%d]", i+1)), name)
+	}
+	if stack.atRoot() {
+		fmt.Fprintf(w, "%s %s\n", t.number(fmt.Sprintf("[%d]", len(menu.Entries)+1)), T("menu.exit"))
+		if last != nil {
+			fmt.Fprintf(w, "%s "+T("menu.last_used")+"\n", t.number("["+T("menu.enter")+"]"), last.localName())
+		}
+	} else {
+		fmt.Fprintf(w, "%s %s\n", t.number("[b]"), T("menu.back"))
+	}
+}
+
+// printHelp describes the entries and commands of the menu on top of
+// stack, taking the text from the registry. last is the app Enter
+// launches, or nil.
+func printHelp(w io.Writer, stack menuStack, last *App) {
+	menu := stack.top()
+	fmt.Fprintln(w, T("help.apps"))
+	for i, e := range menu.Entries {
+		desc := ""
+		if e.Menu != nil {
+			names := make([]string, len(e.Menu.Entries))
+			for j, sub := range e.Menu.Entries {
+				names[j] = sub.Name()
+			}
+			desc = strings.Join(names, ", ")
+		} else {
+			desc = e.App.localDescription()
+		}
+		fmt.Fprintf(w, "  [%d] %s: %s\n", i+1, e.Name(), desc)
+	}
+	fmt.Fprintln(w, T("help.commands"))
+	fmt.Fprintf(w, "  %-12s %s\n", fmt.Sprintf("1-%d", len(menu.Entries)), T("help.open"))
+	if stack.atRoot() {
+		fmt.Fprintf(w, "  %-12d %s\n", len(menu.Entries)+1, T("help.exit"))
+		if last != nil {
+			fmt.Fprintf(w, "  %-12s "+T("help.last")+"\n", T("menu.enter"), last.localName())
+		}
+	} else {
+		fmt.Fprintf(w, "  %-12s %s\n", "b, 0", fmt.Sprintf(T("help.back"), stack[len(stack)-2].Title))
+	}
+	fmt.Fprintf(w, "  %-12s %s\n", "?, help", T("help.help"))
+	fmt.Fprintf(w, "  %-12s %s\n", "version", T("help.version"))
+	fmt.Fprintln(w, T("help.inside"))
+}
+
+// usage prints the command line help, including the apps -app accepts.
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: %s [-version] [-quiet] [-lang code] [-script file] [-app name [-expr expression [-json]]]\n", os.Args[0])
+	flag.PrintDefaults()
+	fmt.Fprintln(out, "Apps:")
+	for _, app := range apps {
+		fmt.Fprintf(out, "  %-12s %s\n", app.ID, app.Name)
+	}
+}
+
+// flagSet reports whether the named flag was given on the command line.
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		set = set || f.Name == name
+	})
+	return set
+}
+
+// badUsage reports a command line mistake and exits with status 2.
+func badUsage(msg string) {
+	fmt.Fprintln(os.Stderr, msg)
+	flag.Usage()
+	os.Exit(2)
+}
+
+func main() {
+	os.Exit(runMain())
+}
+
+// runMain does what main does and returns the exit status, so that
+// deferred calls have run by the time the process exits.
+func runMain() int {
+	start := time.Now()
+	appID := flag.String("app", "", "run the named app directly instead of showing the menu")
+	expr := flag.String("expr", "", "with -app calculator, print the value of `expression` and exit")
+	asJSON := flag.Bool("json", false, "with -expr, print the expression and its value or error as a JSON object")
+	timeout := flag.Duration("timeout", 0, "return to the menu after this long without input (overrides input_timeout in the config)")
+	lang := flag.String("lang", "", "show text in the language with this `code` (en or es) instead of LANG's")
+	scriptPath := flag.String("script", "", "read menu choices and app commands from `file` instead of the keyboard, stopping at the first error; the password is still read from stdin")
+	quiet := flag.Bool("quiet", false, "do not print the session summary when leaving the menu")
+	showVersion := flag.Bool("version", false, "print the version and exit")
+	flag.Usage = usage
+	flag.Parse()
+
+	if *showVersion {
+		fmt.Println(versionString())
+		return 0
+	}
+
+	setLocale(os.Getenv("LANG"))
+	if *lang != "" && !setLocale(*lang) {
+		badUsage(fmt.Sprintf("unsupported language %q", *lang))
+	}
+
+	var app *App
+	if *appID != "" {
+		a, ok := findApp(*appID)
+		if !ok {
+			badUsage(fmt.Sprintf("unknown app %q", *appID))
+		}
+		app = &a
+	}
+	if *expr != "" {
+		if app == nil || app.ID != "calculator" {
+			badUsage("-expr needs -app calculator")
+		}
+		if *scriptPath != "" {
+			badUsage("-expr and -script cannot be used together")
+		}
+		out := newResultWriter(*asJSON, os.Stdout, os.Stderr)
+		result, err := Evaluate(*expr)
+		if err != nil {
+			out.WriteError(*expr, err)
+			return 1
+		}
+		if err := out.WriteResult(*expr, strconv.FormatFloat(result, 'g', -1, 64)); err != nil {
+			fmt.Fprintln(os.Stderr, T("error"), err)
+			return 1
+		}
+		return 0
+	}
+	if *asJSON {
+		badUsage("-json needs -expr")
+	}
+
+	configPath, err := dataPath("config.json")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, T("error"), err)
+		return 1
+	}
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, T("error"), T("main.reading_config"), err)
+		return 1
+	}
+	if app != nil && !config.enabled(app.ID) {
+		fmt.Fprintf(os.Stderr, "%s "+T("main.disabled")+"\n", T("error"), app.ID, configPath)
+		return 1
+	}
+
+	log, closeLog, err := newLogger()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, T("error"), T("main.opening_log"), err)
+		return 1
+	}
+	defer closeLog()
+
+	theme := setupTheme(os.Stdout, config.Color)
+	var in *bufio.Reader
+	var src *interruptReader // nil for scripts, which cannot be interrupted
+	var script *scriptReader
+	var authIn *bufio.Reader // where the password is read from
+	if *scriptPath != "" {
+		var closeScript func() error
+		script, closeScript, err = openScript(*scriptPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, T("error"), err)
+			return 1
+		}
+		defer closeScript()
+		errorHook = func(err error) {
+			fmt.Fprintf(os.Stderr, "%s:%d: %v\n", *scriptPath, script.line, err)
+			script.Stop(err)
+		}
+		in = bufio.NewReader(script)
+		// The password is never kept in the script: whoever runs it types
+		// it, or pipes it in from wherever they keep it.
+		authIn = bufio.NewReader(os.Stdin)
+	} else {
+		// Turn Ctrl+C into an interrupted read so run can say goodbye and
+		// return normally instead of the process dying mid-prompt.
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		src = newInterruptReader(os.Stdin, interrupt)
+		in = bufio.NewReader(src)
+		authIn = in
+	}
+	// Ctrl+C cancels ctx unless an app is running, which gets a context of
+	// its own.
+	ctx, cancel := src.Context(context.Background())
+	defer cancel()
+
+	path, err := dataPath("passwd")
+	if err == nil {
+		err = login(authIn, os.Stdout, path)
+	}
+	if err == io.EOF || errors.Is(err, errInterrupted) {
+		return 0
+	}
+	if err == errLockedOut {
+		fmt.Fprintln(os.Stderr, errLockedOut)
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, T("error"), err)
+		return 1
+	}
 
-		fmt.Println("Press Enter to continue...")
-		fmt.Scanln()
+	// Only prompts after login time out: the password prompt has no menu
+	// to go back to. Scripts never wait for input.
+	if src != nil {
+		src.SetTimeout(time.Duration(config.InputTimeout) * time.Second)
+		if flagSet("timeout") {
+			src.SetTimeout(*timeout)
+		}
+	}
+
+	historyPath, err := dataPath("history.json")
+	if err == nil {
+		history, err = LoadHistory(historyPath)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, T("warning"), T("main.empty_history"), err)
+	}
+
+	env := &Env{In: in, Out: os.Stdout, Log: log, Config: config, Theme: theme, Scripted: *scriptPath != "", Quiet: *quiet}
+	if app != nil {
+		log.Info("launched", "app", app.ID)
+		err := app.Run(ctx, env)
+		if err != nil && !errors.Is(err, errInterrupted) && !errors.Is(err, context.Canceled) && !errors.Is(err, errTimeout) {
+			printError(os.Stderr, err)
+		}
+	} else {
+		env.Session = newSession(start)
+		run(ctx, env, src)
+	}
+
+	if historyPath != "" {
+		if err := SaveHistory(historyPath, GetHistory()); err != nil {
+			fmt.Fprintln(os.Stderr, T("error"), T("main.saving_history"), err)
+		}
+	}
+	if script != nil && script.Err() != nil {
+		return 1
 	}
+	return 0
 }
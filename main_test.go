@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// runMenu drives the menu with input as what is typed and returns what it
+// printed. It is drawn in the theme setupTheme last chose.
+func runMenu(t *testing.T, config Config, input string) string {
+	t.Helper()
+	t.Setenv("DEMO_NO_CLEAR", "1")
+	t.Setenv("HOME", t.TempDir())
+	var out bytes.Buffer
+	env := &Env{
+		In:      strings.NewReader(input),
+		Out:     &out,
+		Log:     slog.New(discardHandler{}),
+		Config:  config,
+		Theme:   theme,
+		Session: newSession(time.Now()),
+		Quiet:   true,
+	}
+	run(context.Background(), env, nil)
+	return out.String()
+}
+
+// menuInput replaces the names in braces in input, such as {Exit} or
+// {Encoder}, with what is typed to pick them on the main menu.
+func menuInput(input string) string {
+	root := buildMenu(defaultConfig.enabledApps())
+	for i, e := range root.Entries {
+		input = strings.ReplaceAll(input, "{"+e.Name()+"}", strconv.Itoa(i+1))
+	}
+	return strings.ReplaceAll(input, "{Exit}", strconv.Itoa(len(root.Entries)+1))
+}
+
+// checkInOrder fails t unless out contains each of want, in order.
+func checkInOrder(t *testing.T, out string, want ...string) {
+	t.Helper()
+	rest := out
+	for _, w := range want {
+		i := strings.Index(rest, w)
+		if i < 0 {
+			t.Fatalf("output is missing %q after the earlier lines:\n%s", w, out)
+		}
+		rest = rest[i+len(w):]
+	}
+}
+
+func TestRunMenu(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string // printed in this order
+	}{
+		{"exit", "{Exit}\n", []string{"Choose app:", "Exit", "Exited"}},
+		{"end of input", "", []string{"Choose app:", "Exited"}},
+		{"calculator", "{Tools}\n1\n2 + 3 * 4\nq\n\nb\n{Exit}\n", []string{"Main > Tools > Calculator", "14", "Press Enter", "Main > Tools", "Exited"}},
+		{"encoder", "{Encoder}\nb64e hi\nq\n\n{Exit}\n", []string{"Main > Encoder", "aGk=", "Exited"}},
+		{"version", "version\n{Exit}\n", []string{versionString(), "Exited"}},
+		{"tools and back", "{Tools}\nb\n{Exit}\n", []string{"Main > Tools", "[b] Back", "Main\n", "Choose app:", "Exited"}},
+		{"tools and back with 0", "{Tools}\n0\n{Exit}\n", []string{"Main > Tools", "Main\n", "Exited"}},
+		{"bad choice", "{Exit}0\n{Exit}\n", []string{"Error:", "Exited"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := runMenu(t, defaultConfig, menuInput(tt.input))
+			checkInOrder(t, out, tt.want...)
+		})
+	}
+}
+
+func TestRunMenuRejectsText(t *testing.T) {
+	out := runMenu(t, defaultConfig, "x\n1\n")
+	exit := len(buildMenu(defaultConfig.enabledApps()).Entries) + 1
+	checkInOrder(t, out, "Error:", "please enter a whole number from 1 to "+strconv.Itoa(exit), "Main > Tools")
+}
+
+func TestReadChoice(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"3\n", 3},
+		{"  3  \n", 3},
+		{"x\n2\n", 2},
+		{"9\n1\n", 1},
+		{"b\n", backChoice},
+		{"0\n", backChoice},
+		{"?\n", helpChoice},
+		{"help\n", helpChoice},
+		{"version\n", versionChoice},
+		{"\n", lastChoice},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		got, err := readChoice(strings.NewReader(tt.input), &out, 5)
+		if err != nil || got != tt.want {
+			t.Errorf("readChoice(%q) = %d, %v; want %d", tt.input, got, err, tt.want)
+		}
+	}
+	if _, err := readChoice(strings.NewReader("x\n"), io.Discard, 5); err != io.EOF {
+		t.Errorf("readChoice at the end of the input: err = %v, want io.EOF", err)
+	}
+}
+
+func TestRunMenuSubmenu(t *testing.T) {
+	out := runMenu(t, defaultConfig, menuInput("{Tools}\n?\n\nb\n{Exit}\n"))
+	start := strings.Index(out, "Main > Tools")
+	end := strings.LastIndex(out, "Main\nChoose app:")
+	if start < 0 || end < start {
+		t.Fatalf("the Tools menu was not shown and left:\n%s", out)
+	}
+	// A submenu goes back rather than exiting.
+	if tools := out[start:end]; strings.Contains(tools, "Exit") {
+		t.Errorf("the Tools menu offers to exit:\n%s", tools)
+	}
+	checkInOrder(t, out[start:end], "[1] Calculator", "[b] Back", "b, 0")
+	checkInOrder(t, out[end:], "[1] Tools >", "Exited")
+}
+
+func TestRunMenuTimeout(t *testing.T) {
+	t.Setenv("DEMO_NO_CLEAR", "1")
+	t.Setenv("HOME", t.TempDir())
+	pr, pw := io.Pipe()
+	src := newInterruptReader(pr, nil)
+	src.SetTimeout(50 * time.Millisecond)
+	go func() {
+		// Open the calculator from Tools and leave it waiting, then pick
+		// Exit, which only the main menu has.
+		io.WriteString(pw, menuInput("{Tools}\n1\n"))
+		time.Sleep(300 * time.Millisecond)
+		io.WriteString(pw, menuInput("{Exit}\n"))
+		pw.Close()
+	}()
+
+	var out bytes.Buffer
+	env := &Env{In: src, Out: &out, Log: slog.New(discardHandler{}), Config: defaultConfig, Session: newSession(time.Now()), Quiet: true}
+	run(context.Background(), env, nil)
+	got := out.String()
+	checkInOrder(t, got, "Main > Tools > Calculator", "Main\nChoose app:", "Exited")
+	if strings.Contains(got, "Error:") {
+		t.Errorf("Exit was not taken from the main menu:\n%s", got)
+	}
+}
+
+func TestRunMenuLastApp(t *testing.T) {
+	// Nothing has been used yet, so Enter only redraws the menu.
+	out := runMenu(t, defaultConfig, menuInput("\n{Exit}\n"))
+	if strings.Contains(out, "last used") || strings.Contains(out, "Main >") {
+		t.Errorf("Enter launched something before any app was used:\n%s", out)
+	}
+
+	out = runMenu(t, defaultConfig, menuInput("{Encoder}\nq\n\n\nb64e hi\nq\n\n{Exit}\n"))
+	checkInOrder(t, out, "Main > Encoder", "[Enter] Encoder (last used)", "Main > Encoder", "aGk=", "Exited")
+	if n := strings.Count(out, "Main > Encoder"); n != 2 {
+		t.Errorf("the encoder was launched %d times, want 2:\n%s", n, out)
+	}
+
+	// The last app can come from the config, and lives in a submenu.
+	config := defaultConfig
+	config.LastApp = "roman"
+	out = runMenu(t, config, menuInput("\nq\n\n{Exit}\n"))
+	checkInOrder(t, out, "[Enter] Roman Numerals (last used)", "Main > Tools > Roman Numerals", "Exited")
+}
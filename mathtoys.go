@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	Register(App{
+		ID:          "mathtoys",
+		Name:        "Math Toys",
+		Description: "list Fibonacci numbers and primes, and test for primes",
+		Usage:       mathToysHelp,
+		Run:         runMathToys,
+	})
+}
+
+// Limits that keep the output readable and the memory use small.
+const (
+	maxFibCount = 1000
+	maxSieve    = 1_000_000
+)
+
+// checkEvery is how many loop steps fibonacci and sieve take between looking
+// at ctx, so that checking costs little but Ctrl+C still stops them at once.
+const checkEvery = 1024
+
+// mathToysHelp is the app's usage, printed by its "help" command.
+var mathToysHelp = fmt.Sprintf(`Commands:
+  fib <n>       the first n Fibonacci numbers (n up to %d)
+  prime <n>     the primes up to n (n up to %d)
+  isprime <n>   whether n is prime (n up to %d)
+  help          show this text
+  q             back to the menu`, maxFibCount, maxSieve, uint64(1<<64-1))
+
+// mathToysCommands are the commands of the math> prompt.
+var mathToysCommands = []string{"fib", "prime", "isprime", "help", "q"}
+
+// fibonacci returns the first n Fibonacci numbers, starting 0, 1, 1, 2, or
+// ctx.Err() if ctx is done first.
+func fibonacci(ctx context.Context, n int) ([]*big.Int, error) {
+	fib := make([]*big.Int, 0, n)
+	a, b := big.NewInt(0), big.NewInt(1)
+	for i := range n {
+		if i%checkEvery == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		fib = append(fib, new(big.Int).Set(a))
+		a.Add(a, b)
+		a, b = b, a
+	}
+	return fib, nil
+}
+
+// sieve returns the primes up to and including n, found with the sieve of
+// Eratosthenes, or ctx.Err() if ctx is done first. Unless report is nil it
+// is called now and then with the number being sieved and n.
+func sieve(ctx context.Context, n int, report func(done, total int)) ([]int, error) {
+	if n < 2 {
+		return nil, nil
+	}
+	composite := make([]bool, n+1)
+	var primes []int
+	steps := 0 // counts both loops: crossing off multiples of 2 alone takes n/2
+	checkpoint := func(i int) error {
+		if report != nil {
+			report(i, n)
+		}
+		return ctx.Err()
+	}
+	for i := 2; i <= n; i++ {
+		if steps++; steps%checkEvery == 0 {
+			if err := checkpoint(i); err != nil {
+				return nil, err
+			}
+		}
+		if composite[i] {
+			continue
+		}
+		primes = append(primes, i)
+		for j := i * i; j <= n; j += i {
+			if steps++; steps%checkEvery == 0 {
+				if err := checkpoint(i); err != nil {
+					return nil, err
+				}
+			}
+			composite[j] = true
+		}
+	}
+	return primes, nil
+}
+
+// isPrime reports whether n is prime. ProbablyPrime is exact below 2^64.
+func isPrime(n uint64) bool {
+	return new(big.Int).SetUint64(n).ProbablyPrime(0)
+}
+
+// runMathToys runs the fib, prime and isprime commands until "q", or until
+// ctx is cancelled during a long fib or prime.
+func runMathToys(ctx context.Context, env *Env) error {
+	r, w := env.In, env.Out
+	fmt.Fprintln(w, T("mathtoys.welcome"))
+	for {
+		fmt.Fprint(w, env.Theme.prompt(T("mathtoys.prompt")))
+		line, err := readLine(r)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		cmd, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+		arg = strings.TrimSpace(arg)
+
+		switch cmd {
+		case "":
+		case "q":
+			return nil
+		case "help":
+			fmt.Fprintln(w, translate("app.mathtoys.usage", mathToysHelp))
+		case "fib":
+			n, ok, err := intArg(r, w, arg, T("mathtoys.how_many"), 0, maxFibCount)
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			fib, err := fibonacci(ctx, n)
+			if err != nil {
+				return err
+			}
+			words := make([]string, len(fib))
+			for i, f := range fib {
+				words[i] = f.String()
+			}
+			fmt.Fprintln(w, strings.Join(words, " "))
+		case "prime":
+			n, ok, err := intArg(r, w, arg, T("mathtoys.up_to"), 0, maxSieve)
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			progress := startProgress(os.Stderr)
+			primes, err := sieve(ctx, n, progress.Report)
+			progress.Stop()
+			if err != nil {
+				return err
+			}
+			words := make([]string, len(primes))
+			for i, p := range primes {
+				words[i] = strconv.Itoa(p)
+			}
+			fmt.Fprintln(w, strings.Join(words, " "))
+		case "isprime":
+			n, err := strconv.ParseUint(arg, 10, 64)
+			if err != nil {
+				printError(w, newError("mathtoys.isprime_usage", uint64(1<<64-1)))
+				continue
+			}
+			if isPrime(n) {
+				fmt.Fprintf(w, T("mathtoys.prime")+"\n", n)
+			} else {
+				fmt.Fprintf(w, T("mathtoys.not_prime")+"\n", n)
+			}
+		default:
+			printError(w, unknownCommand(cmd, mathToysCommands))
+			continue
+		}
+		if cmd != "" && cmd != "help" {
+			env.Log.Info("math", "command", cmd, "arg", arg)
+		}
+	}
+}
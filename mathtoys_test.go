@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSieve(t *testing.T) {
+	tests := []struct {
+		n    int
+		want []int
+	}{
+		{-5, nil},
+		{0, nil},
+		{1, nil},
+		{2, []int{2}},
+		{3, []int{2, 3}},
+		{10, []int{2, 3, 5, 7}},
+		{30, []int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29}},
+		{49, []int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47}},
+	}
+	for _, tt := range tests {
+		if got, err := sieve(context.Background(), tt.n, nil); err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("sieve(%d) = %v, %v; want %v", tt.n, got, err, tt.want)
+		}
+	}
+
+	primes, err := sieve(context.Background(), 100000, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(primes) != 9592 || primes[len(primes)-1] != 99991 {
+		t.Errorf("sieve(100000) found %d primes up to %d, want 9592 up to 99991", len(primes), primes[len(primes)-1])
+	}
+}
+
+func TestFibonacci(t *testing.T) {
+	fib, err := fibonacci(context.Background(), 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int64{0, 1, 1, 2, 3, 5, 8, 13, 21, 34, 55, 89, 144, 233, 377}
+	if len(fib) != len(want) {
+		t.Fatalf("fibonacci(15) returned %d numbers", len(fib))
+	}
+	for i, w := range want {
+		if !fib[i].IsInt64() || fib[i].Int64() != w {
+			t.Errorf("fibonacci(15)[%d] = %v, want %d", i, fib[i], w)
+		}
+	}
+
+	if fib, err := fibonacci(context.Background(), 0); err != nil || len(fib) != 0 {
+		t.Errorf("fibonacci(0) = %v, %v; want none", fib, err)
+	}
+	fib, err = fibonacci(context.Background(), 101)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fib[100].String(); got != "354224848179261915075" {
+		t.Errorf("F(100) = %s", got)
+	}
+}
+
+func TestSieveCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	primes, err := sieve(ctx, 1e8, nil)
+	if err != context.Canceled || primes != nil {
+		t.Errorf("cancelled sieve = %d primes, %v; want context.Canceled", len(primes), err)
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("cancelled sieve took %v to stop", d)
+	}
+}
+
+func TestSieveProgress(t *testing.T) {
+	const n = 100000
+	var done []int
+	report := func(d, total int) {
+		if total != n {
+			t.Fatalf("report(%d, %d): total is not %d", d, total, n)
+		}
+		done = append(done, d)
+	}
+	if _, err := sieve(context.Background(), n, report); err != nil {
+		t.Fatal(err)
+	}
+	if len(done) < 10 {
+		t.Fatalf("progress was reported %d times, want it now and then: %v", len(done), done)
+	}
+	if !slices.IsSorted(done) {
+		t.Errorf("progress went backwards: %v", done)
+	}
+	if first, last := done[0], done[len(done)-1]; first >= last || last > n || first < 2 {
+		t.Errorf("progress went from %d to %d, want increasing from 2 to at most %d", first, last, n)
+	}
+	// The last reports come from the outer loop near the end, with nothing
+	// left to cross off.
+	if last := done[len(done)-1]; last < n-checkEvery {
+		t.Errorf("the last progress was %d, want within %d of %d", last, checkEvery, n)
+	}
+}
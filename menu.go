@@ -0,0 +1,98 @@
+package main
+
+import "strings"
+
+// Menu is one level of the menu: apps and nested menus, in order.
+type Menu struct {
+	Title   string
+	Entries []MenuEntry
+}
+
+// MenuEntry is either an app or a nested menu.
+type MenuEntry struct {
+	App  *App
+	Menu *Menu
+}
+
+// Name is what the entry is listed as.
+func (e MenuEntry) Name() string {
+	if e.Menu != nil {
+		return e.Menu.Title
+	}
+	return e.App.localName()
+}
+
+// buildMenu arranges apps into the main menu. Apps with a Group go into a
+// nested menu of that name, listed where its first app would be.
+func buildMenu(apps []App) *Menu {
+	root := &Menu{Title: T("menu.main")}
+	groups := map[string]*Menu{}
+	for i := range apps {
+		app := &apps[i]
+		if app.Group == "" {
+			root.Entries = append(root.Entries, MenuEntry{App: app})
+			continue
+		}
+		group, ok := groups[app.Group]
+		if !ok {
+			group = &Menu{Title: translate("group."+app.Group, app.Group)}
+			groups[app.Group] = group
+			root.Entries = append(root.Entries, MenuEntry{Menu: group})
+		}
+		group.Entries = append(group.Entries, MenuEntry{App: app})
+	}
+	return root
+}
+
+// menuStack is the path from the main menu to the one being shown, which
+// is last.
+type menuStack []*Menu
+
+func (s menuStack) top() *Menu {
+	return s[len(s)-1]
+}
+
+func (s menuStack) atRoot() bool {
+	return len(s) == 1
+}
+
+func (s *menuStack) push(m *Menu) {
+	*s = append(*s, m)
+}
+
+// pop goes back up one level. At the main menu it does nothing.
+func (s *menuStack) pop() {
+	if !s.atRoot() {
+		*s = (*s)[:len(*s)-1]
+	}
+}
+
+// reset goes back to the main menu.
+func (s *menuStack) reset() {
+	*s = (*s)[:1]
+}
+
+// breadcrumb names the menus on the stack, like "Main > Tools".
+func (s menuStack) breadcrumb() string {
+	titles := make([]string, len(s))
+	for i, m := range s {
+		titles[i] = m.Title
+	}
+	return strings.Join(titles, " > ")
+}
+
+// find returns the path from m to the menu listing the app with the given
+// ID, and that app. The path is nil if no menu under m lists it.
+func (m *Menu) find(id string) (menuStack, *App) {
+	for _, e := range m.Entries {
+		if e.App != nil && e.App.ID == id {
+			return menuStack{m}, e.App
+		}
+		if e.Menu != nil {
+			if path, app := e.Menu.find(id); path != nil {
+				return append(menuStack{m}, path...), app
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// ResultWriter reports the outcome of evaluating an expression, given with
+// -expr or typed at the calculator. The result is the number as the
+// calculator shows it.
+type ResultWriter interface {
+	WriteResult(expr, result string) error
+	WriteError(expr string, err error) error
+}
+
+// newResultWriter returns the writer -json asks for: JSON on out, or else
+// plain text with results on out and errors on errOut. JSON results must
+// be plain decimal numbers, not shown in another base or with grouping.
+func newResultWriter(asJSON bool, out, errOut io.Writer) ResultWriter {
+	if asJSON {
+		return jsonResults{out}
+	}
+	return textResults{out, errOut}
+}
+
+// textResults prints results and errors the way a person reads them.
+type textResults struct {
+	out, errOut io.Writer
+}
+
+func (t textResults) WriteResult(_, result string) error {
+	_, err := fmt.Fprintln(t.out, result)
+	return err
+}
+
+// WriteError shows err with printError, so it is in the theme's color and
+// stops a script.
+func (t textResults) WriteError(_ string, err error) error {
+	printError(t.errOut, err)
+	return nil
+}
+
+// jsonResults writes one JSON object per expression, with either a result
+// or an error, for other programs to read.
+type jsonResults struct {
+	w io.Writer
+}
+
+type jsonResult struct {
+	Expr   string      `json:"expr"`
+	Result json.Number `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+func (j jsonResults) write(r jsonResult) error {
+	enc := json.NewEncoder(j.w)
+	enc.SetEscapeHTML(false) // keep "1 << 2" readable
+	return enc.Encode(r)
+}
+
+func (j jsonResults) WriteResult(expr, result string) error {
+	return j.write(jsonResult{Expr: expr, Result: json.Number(result)})
+}
+
+func (j jsonResults) WriteError(expr string, err error) error {
+	return j.write(jsonResult{Expr: expr, Error: err.Error()})
+}
+
+// field is one line of a report such as the stats app prints: a label,
+// looked up with T, and a value.
+type field struct {
+	key, value string
+}
+
+// printFields prints fields one per line with their values lined up two
+// spaces after the longest label.
+func printFields(w io.Writer, fields []field) {
+	width := 0
+	for _, f := range fields {
+		width = max(width, utf8.RuneCountInString(T(f.key)))
+	}
+	for _, f := range fields {
+		fmt.Fprintf(w, "%-*s  %s\n", width, T(f.key), f.value)
+	}
+}
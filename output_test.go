@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// writeExpr evaluates expr and reports it through the -json writer, the way
+// -expr does, and returns the JSON object written.
+func writeExpr(t *testing.T, expr string) (line string, fields map[string]any) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	w := newResultWriter(true, &out, &errOut)
+	if result, err := Evaluate(expr); err != nil {
+		w.WriteError(expr, err)
+	} else {
+		w.WriteResult(expr, strconv.FormatFloat(result, 'g', -1, 64))
+	}
+	if errOut.Len() != 0 {
+		t.Errorf("%s: JSON mode wrote to stderr: %q", expr, errOut.String())
+	}
+	if err := json.Unmarshal(out.Bytes(), &fields); err != nil {
+		t.Fatalf("%s: output %q is not one JSON object: %v", expr, out.String(), err)
+	}
+	return out.String(), fields
+}
+
+func TestJSONResults(t *testing.T) {
+	line, fields := writeExpr(t, "2+2")
+	if want := `{"expr":"2+2","result":4}` + "\n"; line != want {
+		t.Errorf("success = %q, want %q", line, want)
+	}
+	if want := map[string]any{"expr": "2+2", "result": 4.0}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("success fields = %v, want %v", fields, want)
+	}
+
+	// A result of zero is still there, and < and > are not escaped.
+	line, _ = writeExpr(t, "1 << 2 >> 3")
+	if want := `{"expr":"1 << 2 >> 3","result":0}` + "\n"; line != want {
+		t.Errorf("zero result = %q, want %q", line, want)
+	}
+
+	// Large and small results are still JSON numbers.
+	for _, expr := range []string{"2^70", "1 / 3^20", "0.1 + 0.2"} {
+		_, fields := writeExpr(t, expr)
+		want, _ := Evaluate(expr)
+		if got, ok := fields["result"].(float64); !ok || got != want {
+			t.Errorf("%s: result = %v, want the number %v", expr, fields["result"], want)
+		}
+	}
+
+	line, fields = writeExpr(t, "1 / 0")
+	if want := `{"expr":"1 / 0","error":"cannot divide by zero"}` + "\n"; line != want {
+		t.Errorf("error = %q, want %q", line, want)
+	}
+	if _, ok := fields["result"]; ok {
+		t.Errorf("an error has a result: %v", fields)
+	}
+}
+
+func TestTextResults(t *testing.T) {
+	var out, errOut bytes.Buffer
+	w := newResultWriter(false, &out, &errOut)
+	w.WriteResult("2+2", "4")
+	w.WriteError("1 / 0", errDivideByZero)
+	if got, want := out.String(), "4\n"; got != want {
+		t.Errorf("stdout = %q, want %q", got, want)
+	}
+	if got, want := errOut.String(), "Error: cannot divide by zero\n"; got != want {
+		t.Errorf("stderr = %q, want %q", got, want)
+	}
+}
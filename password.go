@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordPolicy lists the rules a password has to satisfy.
+type PasswordPolicy struct {
+	MinLength     int // counted in runes, not bytes
+	RequireDigit  bool
+	RequireUpper  bool
+	RequireSymbol bool
+}
+
+// defaultPasswordPolicy is the policy new passwords have to satisfy.
+var defaultPasswordPolicy = PasswordPolicy{
+	MinLength:    8,
+	RequireDigit: true,
+	RequireUpper: true,
+}
+
+// Validate returns a message for every rule pw breaks.
+// An empty slice means the password is acceptable.
+func (p PasswordPolicy) Validate(pw string) []string {
+	problems := []string{}
+	if utf8.RuneCountInString(pw) < p.MinLength {
+		problems = append(problems, fmt.Sprintf(T("password.length"), p.MinLength))
+	}
+
+	var digit, upper, symbol bool
+	for _, c := range pw {
+		switch {
+		case unicode.IsDigit(c):
+			digit = true
+		case unicode.IsUpper(c):
+			upper = true
+		case unicode.IsPunct(c) || unicode.IsSymbol(c):
+			symbol = true
+		}
+	}
+	if p.RequireDigit && !digit {
+		problems = append(problems, T("password.digit"))
+	}
+	if p.RequireUpper && !upper {
+		problems = append(problems, T("password.upper"))
+	}
+	if p.RequireSymbol && !symbol {
+		problems = append(problems, T("password.symbol"))
+	}
+	return problems
+}
+
+// reportPasswordProblems prints every rule of the default policy that
+// password breaks and reports whether there were none.
+func reportPasswordProblems(w io.Writer, password string) bool {
+	problems := defaultPasswordPolicy.Validate(password)
+	for _, problem := range problems {
+		fmt.Fprintln(w, problem)
+	}
+	return len(problems) == 0
+}
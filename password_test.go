@@ -0,0 +1,47 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	strict := PasswordPolicy{MinLength: 10, RequireDigit: true, RequireUpper: true, RequireSymbol: true}
+	const (
+		length = "Password must be at least 10 characters long."
+		digit  = "Password must contain at least one digit."
+		upper  = "Password must contain at least one uppercase letter."
+		symbol = "Password must contain at least one symbol."
+	)
+	tests := []struct {
+		pw   string
+		want []string
+	}{
+		{"", []string{length, digit, upper, symbol}},
+		{"short", []string{length, digit, upper, symbol}},
+		{"longenoughbutplain", []string{digit, upper, symbol}},
+		{"Longenough1", []string{symbol}},
+		{"Longenough!", []string{digit}},
+		{"longenough1!", []string{upper}},
+		{"Short1!", []string{length}},
+		{"Longenough1!", []string{}},
+		// Ten runes but twenty bytes: long enough.
+		{"Ñandú1!ééé", []string{}},
+		// Nine runes but more than ten bytes: too short.
+		{"Ñandú1!éé", []string{length}},
+	}
+	for _, tt := range tests {
+		if got := strict.Validate(tt.pw); !slices.Equal(got, tt.want) {
+			t.Errorf("Validate(%q) = %q, want %q", tt.pw, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultPasswordPolicy(t *testing.T) {
+	if got := defaultPasswordPolicy.Validate("GoodPass1"); len(got) != 0 {
+		t.Errorf("the default policy rejects GoodPass1: %q", got)
+	}
+	if got := defaultPasswordPolicy.Validate("goodpass1"); !slices.Equal(got, []string{"Password must contain at least one uppercase letter."}) {
+		t.Errorf("Validate(goodpass1) = %q", got)
+	}
+}
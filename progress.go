@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// A computation has to run for progressDelay before its progress is shown,
+// so quick ones do not make the screen flicker. The spinner then moves every
+// progressTick.
+const (
+	progressDelay = 200 * time.Millisecond
+	progressTick  = 100 * time.Millisecond
+)
+
+var spinnerFrames = []string{"|", "/", "-", `\`}
+
+// Progress shows a spinner while a long computation runs, followed by how
+// far it has got once the computation calls Report. It is drawn on one line
+// that is erased again afterwards, and only when the writer is a terminal.
+type Progress struct {
+	w       io.Writer
+	percent atomic.Int64  // -1 until Report is called
+	stop    chan struct{} // nil when nothing is shown
+	stopped chan struct{}
+}
+
+// startProgress starts showing progress on w, usually os.Stderr so results
+// on stdout are not mixed up with it. Stop must be called however the
+// computation ends.
+func startProgress(w io.Writer) *Progress {
+	p := &Progress{w: w}
+	p.percent.Store(-1)
+	if f, ok := w.(*os.File); !ok || !term.IsTerminal(int(f.Fd())) {
+		return p
+	}
+	p.stop, p.stopped = make(chan struct{}), make(chan struct{})
+	go p.show()
+	return p
+}
+
+func (p *Progress) show() {
+	defer close(p.stopped)
+	delay := time.NewTimer(progressDelay)
+	defer delay.Stop()
+	select {
+	case <-p.stop:
+		return
+	case <-delay.C:
+	}
+
+	tick := time.NewTicker(progressTick)
+	defer tick.Stop()
+	for frame := 0; ; frame++ {
+		line := spinnerFrames[frame%len(spinnerFrames)]
+		if percent := p.percent.Load(); percent >= 0 {
+			line += fmt.Sprintf(" %d%%", percent)
+		}
+		fmt.Fprint(p.w, "\r"+line+"\033[K")
+		select {
+		case <-p.stop:
+			fmt.Fprint(p.w, "\r\033[K")
+			return
+		case <-tick.C:
+		}
+	}
+}
+
+// Report records that done of total steps are finished. Its method value
+// is what computations such as sieve take as their progress callback.
+func (p *Progress) Report(done, total int) {
+	if total > 0 {
+		p.percent.Store(int64(done) * 100 / int64(total))
+	}
+}
+
+// Stop stops showing progress and returns once the line is erased.
+func (p *Progress) Stop() {
+	if p.stop == nil {
+		return
+	}
+	close(p.stop)
+	<-p.stopped
+}
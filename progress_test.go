@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestProgressReport(t *testing.T) {
+	var out bytes.Buffer
+	p := startProgress(&out)
+	if got := p.percent.Load(); got != -1 {
+		t.Errorf("percent before any Report = %d, want -1", got)
+	}
+	tests := []struct {
+		done, total int
+		want        int64
+	}{
+		{0, 200, 0},
+		{1, 200, 0},
+		{50, 200, 25},
+		{199, 200, 99},
+		{200, 200, 100},
+		{5, 0, 100}, // no total: kept as it was
+	}
+	for _, tt := range tests {
+		p.Report(tt.done, tt.total)
+		if got := p.percent.Load(); got != tt.want {
+			t.Errorf("after Report(%d, %d): percent = %d, want %d", tt.done, tt.total, got, tt.want)
+		}
+	}
+	p.Stop()
+	// A buffer is not a terminal, so nothing was drawn.
+	if out.Len() != 0 {
+		t.Errorf("progress drawn on a buffer: %q", out.String())
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func init() {
+	Register(App{
+		ID:          "roman",
+		Name:        "Roman Numerals",
+		Description: "convert whole numbers to Roman numerals and back",
+		Usage:       romanHelp,
+		Group:       "Tools",
+		Run:         runRoman,
+	})
+}
+
+// The numbers Roman numerals can write without a bar over the letters.
+const (
+	minRoman = 1
+	maxRoman = 3999
+)
+
+// romanHelp is the app's usage, printed by its "help" command.
+var romanHelp = fmt.Sprintf(`Commands:
+  to <n>      n as a Roman numeral (n from %d to %d)
+  from <s>    the number the Roman numeral s stands for
+  help        show this text
+  q           back to the menu`, minRoman, maxRoman)
+
+// romanCommands are the commands of the roman> prompt.
+var romanCommands = []string{"to", "from", "help", "q"}
+
+// romanDigits pairs each letter, and each subtractive pair like CM, with
+// its value, largest first.
+var romanDigits = []struct {
+	value   int
+	numeral string
+}{
+	{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"},
+	{100, "C"}, {90, "XC"}, {50, "L"}, {40, "XL"},
+	{10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+}
+
+// toRoman writes n, from 1 to 3999, as a Roman numeral.
+func toRoman(n int) (string, error) {
+	if n < minRoman || n > maxRoman {
+		return "", newError("roman.range", minRoman, maxRoman)
+	}
+	var b strings.Builder
+	for _, d := range romanDigits {
+		for n >= d.value {
+			b.WriteString(d.numeral)
+			n -= d.value
+		}
+	}
+	return b.String(), nil
+}
+
+// fromRoman returns the number the Roman numeral s stands for, in either
+// case. Only the standard form toRoman writes is accepted, so "IIII",
+// "VV", "IC" and "XM" are errors rather than guesses.
+func fromRoman(s string) (int, error) {
+	upper := strings.ToUpper(s)
+	n, rest := 0, upper
+	for _, d := range romanDigits {
+		for strings.HasPrefix(rest, d.numeral) {
+			n += d.value
+			rest = rest[len(d.numeral):]
+		}
+	}
+	if rest != "" || n == 0 {
+		return 0, newError("roman.bad", s)
+	}
+	// Reading greedily accepts anything made of the right letters in the
+	// right order, like "IIII" or "XCX", so only a numeral that comes back
+	// the same is well formed.
+	canonical, err := toRoman(n)
+	if err != nil {
+		return 0, newError("roman.malformed", s)
+	}
+	if canonical != upper {
+		return 0, newError("roman.canonical", s, n, canonical)
+	}
+	return n, nil
+}
+
+// runRoman runs the to and from commands until "q".
+func runRoman(_ context.Context, env *Env) error {
+	r, w := env.In, env.Out
+	fmt.Fprintln(w, T("roman.welcome"))
+	for {
+		fmt.Fprint(w, env.Theme.prompt(T("roman.prompt")))
+		line, err := readLine(r)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		cmd, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+		arg = strings.TrimSpace(arg)
+
+		switch cmd {
+		case "":
+		case "q":
+			return nil
+		case "help":
+			fmt.Fprintln(w, translate("app.roman.usage", romanHelp))
+		case "to":
+			n, ok, err := intArg(r, w, arg, T("roman.number"), minRoman, maxRoman)
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			numeral, err := toRoman(n)
+			if err != nil {
+				printError(w, err)
+				continue
+			}
+			fmt.Fprintf(w, "%d = %s\n", n, numeral)
+		case "from":
+			if arg == "" {
+				printError(w, newError("roman.from_usage"))
+				continue
+			}
+			n, err := fromRoman(arg)
+			if err != nil {
+				printError(w, err)
+				continue
+			}
+			fmt.Fprintf(w, "%s = %d\n", strings.ToUpper(arg), n)
+		default:
+			printError(w, unknownCommand(cmd, romanCommands))
+			continue
+		}
+		if cmd != "" && cmd != "help" {
+			env.Log.Info("roman", "command", cmd, "arg", arg)
+		}
+	}
+}
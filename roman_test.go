@@ -0,0 +1,78 @@
+package main
+
+import "testing"
+
+func TestToRoman(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{1, "I"},
+		{4, "IV"},
+		{9, "IX"},
+		{14, "XIV"},
+		{40, "XL"},
+		{90, "XC"},
+		{400, "CD"},
+		{1994, "MCMXCIV"},
+		{2024, "MMXXIV"},
+		{3888, "MMMDCCCLXXXVIII"},
+		{3999, "MMMCMXCIX"},
+	}
+	for _, tt := range tests {
+		if got, err := toRoman(tt.n); err != nil || got != tt.want {
+			t.Errorf("toRoman(%d) = %q, %v; want %q", tt.n, got, err, tt.want)
+		}
+	}
+	for _, n := range []int{0, -1, 4000} {
+		if _, err := toRoman(n); err == nil || err.Error() != "only numbers from 1 to 3999 can be written as Roman numerals" {
+			t.Errorf("toRoman(%d) error = %v", n, err)
+		}
+	}
+}
+
+func TestFromRoman(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"I", 1},
+		{"MCMXCIV", 1994},
+		{"mcmxciv", 1994},
+		{"McMxCiV", 1994},
+		{"XLII", 42},
+		{"MMMCMXCIX", 3999},
+	}
+	for _, tt := range tests {
+		if got, err := fromRoman(tt.s); err != nil || got != tt.want {
+			t.Errorf("fromRoman(%q) = %d, %v; want %d", tt.s, got, err, tt.want)
+		}
+	}
+	for n := 1; n <= 3999; n++ {
+		s, _ := toRoman(n)
+		if got, err := fromRoman(s); err != nil || got != n {
+			t.Fatalf("fromRoman(toRoman(%d)) = %d, %v", n, got, err)
+		}
+	}
+}
+
+func TestFromRomanErrors(t *testing.T) {
+	tests := []struct {
+		s, want string
+	}{
+		{"IIII", `"IIII" is not a well-formed Roman numeral; 4 is written IV`},
+		{"VV", `"VV" is not a well-formed Roman numeral; 10 is written X`},
+		{"XCX", `"XCX" is not a well-formed Roman numeral; 100 is written C`},
+		{"IC", `"IC" is not a Roman numeral`},
+		{"XM", `"XM" is not a Roman numeral`},
+		{"MMMM", `"MMMM" is not a well-formed Roman numeral`},
+		{"", `"" is not a Roman numeral`},
+		{"ABC", `"ABC" is not a Roman numeral`},
+		{"12", `"12" is not a Roman numeral`},
+	}
+	for _, tt := range tests {
+		if _, err := fromRoman(tt.s); err == nil || err.Error() != tt.want {
+			t.Errorf("fromRoman(%q) error = %v, want %q", tt.s, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// clearScreen clears the terminal that w writes to. Setting DEMO_NO_CLEAR
+// turns it off so earlier output stays scrollable while debugging.
+func clearScreen(w io.Writer) {
+	if os.Getenv("DEMO_NO_CLEAR") != "" {
+		return
+	}
+	if runtime.GOOS == "windows" {
+		// cmd.exe does not understand ANSI escapes.
+		cmd := exec.Command("cmd", "/c", "cls")
+		cmd.Stdout = w
+		cmd.Run()
+		return
+	}
+	fmt.Fprint(w, "\033[H\033[2J")
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+)
+
+// scriptReader feeds the lines of a -script file to the menu and apps as
+// if they were typed, skipping blank lines and lines starting with "#".
+type scriptReader struct {
+	scanner *bufio.Scanner
+	line    int    // number in the file of the latest line read
+	pending []byte // what is left of that line
+	err     error  // why the script was stopped, if it was
+}
+
+func newScriptReader(r io.Reader) *scriptReader {
+	return &scriptReader{scanner: bufio.NewScanner(r)}
+}
+
+// Read returns at most one line per call, so a bufio.Reader on top never
+// reads ahead and line is always the line being run.
+func (s *scriptReader) Read(p []byte) (int, error) {
+	if s.err != nil {
+		return 0, io.EOF
+	}
+	for len(s.pending) == 0 {
+		if !s.scanner.Scan() {
+			if err := s.scanner.Err(); err != nil {
+				return 0, err
+			}
+			return 0, io.EOF
+		}
+		s.line++
+		text := strings.TrimSpace(s.scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		s.pending = []byte(s.scanner.Text() + "\n")
+	}
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+// Stop ends the script because of err: the rest of it is skipped, so the
+// menu and apps finish as they would at the end of the file.
+func (s *scriptReader) Stop(err error) {
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+// Err returns the error the script was stopped for, or nil.
+func (s *scriptReader) Err() error {
+	return s.err
+}
+
+// openScript opens the script at path. The returned function closes it.
+func openScript(path string) (*scriptReader, func() error, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return newScriptReader(f), f.Close, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScriptReader(t *testing.T) {
+	s := newScriptReader(strings.NewReader("# a comment\n1\n\n   \n  2 + 3  \n# the end\n"))
+	var lines []string
+	var numbers []int
+	for {
+		line, err := readLine(s)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, line)
+		numbers = append(numbers, s.line)
+	}
+	if want := []string{"1", "  2 + 3  "}; strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("read %q, want %q", lines, want)
+	}
+	if numbers[0] != 2 || numbers[1] != 5 {
+		t.Errorf("line numbers %v, want [2 5]", numbers)
+	}
+}
+
+func TestScriptStopsAtFirstError(t *testing.T) {
+	t.Setenv("DEMO_NO_CLEAR", "1")
+	t.Setenv("HOME", t.TempDir())
+	script := newScriptReader(strings.NewReader(menuInput("{Tools}\n1\n2 + 2\n1 / 0\n3 * 3\nq\nb\n{Encoder}\n")))
+	var failedAt int
+	errorHook = func(err error) {
+		failedAt = script.line
+		script.Stop(err)
+	}
+	t.Cleanup(func() { errorHook = nil })
+
+	var out bytes.Buffer
+	env := &Env{In: script, Out: &out, Log: slog.New(discardHandler{}), Config: defaultConfig, Scripted: true, Session: newSession(time.Now()), Quiet: true}
+	run(context.Background(), env, nil)
+
+	got := out.String()
+	checkInOrder(t, got, "4\n", "Error: cannot divide by zero", "Exited")
+	if strings.Contains(got, "9\n") || strings.Contains(got, "Main > Encoder") {
+		t.Errorf("the script went on after the error:\n%s", got)
+	}
+	if failedAt != 4 || !errors.Is(script.Err(), errDivideByZero) {
+		t.Errorf("stopped at line %d with %v, want line 4 and the division by zero", failedAt, script.Err())
+	}
+	if n, err := script.Read(make([]byte, 10)); n != 0 || err != io.EOF {
+		t.Errorf("Read after Stop = %d, %v; want io.EOF", n, err)
+	}
+}
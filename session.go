@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Session is what the menu keeps track of from one launch to the next, for
+// Enter and for the summary printed on exit.
+type Session struct {
+	Start, End   time.Time      // End is set when the session is over
+	Picked       map[string]int // launches by app ID
+	LastApp      string         // ID of the latest app launched, "" if none yet
+	Calculations int            // expressions the calculator computed
+}
+
+func newSession(start time.Time) *Session {
+	return &Session{Start: start, Picked: make(map[string]int)}
+}
+
+// launched records that the app with the given ID was started.
+func (s *Session) launched(id string) {
+	s.Picked[id]++
+	s.LastApp = id
+}
+
+// calculated counts one calculation. A nil *Session, as apps run outside
+// the menu have, counts nothing.
+func (s *Session) calculated() {
+	if s != nil {
+		s.Calculations++
+	}
+}
+
+// summarize describes s: how long it lasted, the apps used, in menu order,
+// and the number of calculations.
+func summarize(s Session) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, T("summary.duration")+"\n", formatDuration(s.End.Sub(s.Start)))
+	used := false
+	for _, app := range apps {
+		if n := s.Picked[app.ID]; n > 0 {
+			if !used {
+				fmt.Fprintln(&b, T("summary.apps"))
+				used = true
+			}
+			fmt.Fprintf(&b, T("menu.selected")+"\n", app.localName(), n)
+		}
+	}
+	if !used {
+		fmt.Fprintln(&b, T("summary.no_apps"))
+	}
+	fmt.Fprintf(&b, T("summary.calculations"), s.Calculations)
+	return b.String()
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	s := Session{
+		Start: start,
+		End:   start.Add(2*time.Minute + 5500*time.Millisecond),
+		// In menu order, not launch order, and unknown IDs left out.
+		Picked:       map[string]int{"roman": 1, "calculator": 3, "encode": 2, "gone": 4},
+		Calculations: 7,
+	}
+	want := "Session lasted 2m05.5s.\n" +
+		"Apps used:\n" +
+		"  Calculator: selected 3 time(s)\n" +
+		"  Encoder: selected 2 time(s)\n" +
+		"  Roman Numerals: selected 1 time(s)\n" +
+		"Calculations: 7"
+	if got := summarize(s); got != want {
+		t.Errorf("summarize =\n%s\nwant\n%s", got, want)
+	}
+
+	empty := *newSession(start)
+	empty.End = start.Add(300 * time.Millisecond)
+	want = "Session lasted 0.3s.\nNo apps used.\nCalculations: 0"
+	if got := summarize(empty); got != want {
+		t.Errorf("summarize(no apps) =\n%s\nwant\n%s", got, want)
+	}
+
+	useLocale(t, "es")
+	want = "La sesión ha durado 0.3s.\nNo se ha usado ninguna aplicación.\nCálculos: 0"
+	if got := summarize(empty); got != want {
+		t.Errorf("summarize in Spanish =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSessionCounts(t *testing.T) {
+	s := newSession(time.Now())
+	s.launched("calculator")
+	s.launched("encode")
+	s.launched("calculator")
+	s.calculated()
+	s.calculated()
+	if s.Picked["calculator"] != 2 || s.Picked["encode"] != 1 || s.LastApp != "calculator" || s.Calculations != 2 {
+		t.Errorf("session after three launches and two calculations: %+v", s)
+	}
+	var none *Session
+	none.calculated() // outside the menu, counts nothing
+}
+
+func TestExitQuiet(t *testing.T) {
+	var out bytes.Buffer
+	exit(&out, nil, newSession(time.Now()), true)
+	if got := out.String(); got != "Exited\n" {
+		t.Errorf("quiet exit printed %q, want only Exited", got)
+	}
+	out.Reset()
+	exit(&out, nil, newSession(time.Now()), false)
+	checkInOrder(t, out.String(), "Exited\n", "Session lasted", "No apps used.", "Calculations: 0")
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	Register(App{
+		ID:          "stats",
+		Name:        "Stats",
+		Description: "count, sum, mean, median, min, max and standard deviation of numbers",
+		Usage:       statsHelp,
+		Run:         runStats,
+	})
+}
+
+// statsHelp is the app's usage, printed by its "help" command.
+const statsHelp = `Enter numbers separated by spaces or one per line, then a blank line
+or "." to see their statistics. Anything that is not a number is skipped.
+Type q to go back to the menu.`
+
+// mean returns the average of xs, or NaN if there are none.
+func mean(xs []float64) float64 {
+	sum := 0.0
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+// median returns the middle value of xs, or the average of the two middle
+// values when there is an even number of them. It is NaN if there are
+// none. xs is not modified.
+func median(xs []float64) float64 {
+	if len(xs) == 0 {
+		return math.NaN()
+	}
+	sorted := slices.Clone(xs)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// stddev returns the population standard deviation of xs, or NaN if there
+// are none.
+func stddev(xs []float64) float64 {
+	m := mean(xs)
+	sum := 0.0
+	for _, x := range xs {
+		sum += (x - m) * (x - m)
+	}
+	return math.Sqrt(sum / float64(len(xs)))
+}
+
+// printStats prints the statistics of xs, which must not be empty.
+func printStats(w io.Writer, xs []float64) {
+	sum := 0.0
+	for _, x := range xs {
+		sum += x
+	}
+	g := func(v float64) string { return strconv.FormatFloat(v, 'g', 10, 64) }
+	printFields(w, []field{
+		{"stats.count", strconv.Itoa(len(xs))},
+		{"stats.sum", g(sum)},
+		{"stats.mean", g(mean(xs))},
+		{"stats.median", g(median(xs))},
+		{"stats.min", g(slices.Min(xs))},
+		{"stats.max", g(slices.Max(xs))},
+		{"stats.stddev", g(stddev(xs))},
+	})
+}
+
+// runStats reads lists of numbers, each ended by a blank line or ".", and
+// prints their statistics, until the user types "q" or the input ends.
+func runStats(_ context.Context, env *Env) error {
+	r, w := env.In, env.Out
+	fmt.Fprintln(w, T("stats.welcome"))
+	var xs []float64
+	for {
+		line, err := readLine(r)
+		if err != nil && err != io.EOF {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		if line == "q" {
+			return nil
+		}
+		if line == "help" {
+			fmt.Fprintln(w, translate("app.stats.usage", statsHelp))
+			continue
+		}
+		if err == io.EOF || line == "" || line == "." {
+			if len(xs) > 0 {
+				env.Log.Info("stats", "count", len(xs))
+				printStats(w, xs)
+				xs = nil
+			}
+			if err == io.EOF {
+				return nil
+			}
+			continue
+		}
+
+		for _, field := range strings.Fields(line) {
+			x, err := strconv.ParseFloat(field, 64)
+			if err != nil || math.IsInf(x, 0) || math.IsNaN(x) {
+				fmt.Fprintf(w, "%s "+T("stats.skipping")+"\n", T("warning"), field)
+				continue
+			}
+			xs = append(xs, x)
+		}
+	}
+}
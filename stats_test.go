@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		xs                   []float64
+		mean, median, stddev float64
+	}{
+		{[]float64{5}, 5, 5, 0},
+		{[]float64{1, 2, 3}, 2, 2, math.Sqrt(2.0 / 3)},
+		{[]float64{4, 1, 3, 2}, 2.5, 2.5, math.Sqrt(1.25)},
+		{[]float64{2, 4, 4, 4, 5, 5, 7, 9}, 5, 4.5, 2},
+		{[]float64{10, -10}, 0, 0, 10},
+		{[]float64{3, 1, 2, 100}, 26.5, 2.5, math.Sqrt(1801.25)},
+	}
+	for _, tt := range tests {
+		before := slices.Clone(tt.xs)
+		if got := mean(tt.xs); got != tt.mean {
+			t.Errorf("mean(%v) = %v, want %v", tt.xs, got, tt.mean)
+		}
+		if got := median(tt.xs); got != tt.median {
+			t.Errorf("median(%v) = %v, want %v", tt.xs, got, tt.median)
+		}
+		if got := stddev(tt.xs); math.Abs(got-tt.stddev) > 1e-12 {
+			t.Errorf("stddev(%v) = %v, want %v", tt.xs, got, tt.stddev)
+		}
+		if !slices.Equal(tt.xs, before) {
+			t.Errorf("median sorted its argument: %v", tt.xs)
+		}
+	}
+	for name, f := range map[string]func([]float64) float64{"mean": mean, "median": median, "stddev": stddev} {
+		if got := f(nil); !math.IsNaN(got) {
+			t.Errorf("%s(nil) = %v, want NaN", name, got)
+		}
+	}
+}
+
+func TestPrintStats(t *testing.T) {
+	var out bytes.Buffer
+	printStats(&out, []float64{2, 4, 4, 4, 5, 5, 7, 9})
+	const want = "count   8\nsum     40\nmean    5\nmedian  4.5\nmin     2\nmax     9\nstddev  2\n"
+	if out.String() != want {
+		t.Errorf("printStats printed\n%s\nwant\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register(App{
+		ID:          "stopwatch",
+		Name:        "Stopwatch",
+		Description: "time things, with laps",
+		Usage:       stopwatchHelp,
+		Run:         runStopwatch,
+	})
+}
+
+var (
+	errRunning    = newError("stopwatch.running")
+	errNotRunning = newError("stopwatch.not_running")
+)
+
+// Stopwatch measures elapsed time, which can be paused with Stop and
+// resumed with Start, and records lap splits.
+type Stopwatch struct {
+	clock   clock
+	running bool
+	started time.Time     // when the current run began
+	stored  time.Duration // time accumulated by earlier runs
+	laps    []time.Duration
+}
+
+// NewStopwatch returns a stopped Stopwatch that tells time with c.
+func NewStopwatch(c clock) *Stopwatch {
+	return &Stopwatch{clock: c}
+}
+
+// Start starts or resumes timing.
+func (s *Stopwatch) Start() error {
+	if s.running {
+		return errRunning
+	}
+	s.running = true
+	s.started = s.clock.Now()
+	return nil
+}
+
+// Stop pauses timing and returns the total elapsed time.
+func (s *Stopwatch) Stop() (time.Duration, error) {
+	if !s.running {
+		return 0, errNotRunning
+	}
+	s.stored = s.Elapsed()
+	s.running = false
+	return s.stored, nil
+}
+
+// Lap records and returns the elapsed time so far as a split.
+func (s *Stopwatch) Lap() (time.Duration, error) {
+	if !s.running {
+		return 0, errNotRunning
+	}
+	split := s.Elapsed()
+	s.laps = append(s.laps, split)
+	return split, nil
+}
+
+// Reset stops the stopwatch and clears the time and laps.
+func (s *Stopwatch) Reset() {
+	*s = Stopwatch{clock: s.clock}
+}
+
+// Elapsed returns the total time measured so far.
+func (s *Stopwatch) Elapsed() time.Duration {
+	if !s.running {
+		return s.stored
+	}
+	return s.stored + s.clock.Now().Sub(s.started)
+}
+
+// Laps returns the recorded splits in order.
+func (s *Stopwatch) Laps() []time.Duration {
+	return append([]time.Duration(nil), s.laps...)
+}
+
+// formatDuration shows d to a tenth of a second, for example 1m05.3s.
+func formatDuration(d time.Duration) string {
+	d = d.Round(100 * time.Millisecond)
+	h, m := int(d/time.Hour), int(d%time.Hour/time.Minute)
+	sec := (d % time.Minute).Seconds()
+	switch {
+	case h > 0:
+		return fmt.Sprintf("%dh%02dm%04.1fs", h, m, sec)
+	case m > 0:
+		return fmt.Sprintf("%dm%04.1fs", m, sec)
+	}
+	return fmt.Sprintf("%.1fs", sec)
+}
+
+// stopwatchHelp is the stopwatch's usage, printed by its "help" command.
+const stopwatchHelp = `Commands:
+  start   start or resume timing
+  lap     show the time so far and record it as a lap
+  stop    pause and show the total
+  reset   clear the time and laps
+  help    show this text
+  q       back to the menu`
+
+// stopwatchCommands are the commands runStopwatch knows.
+var stopwatchCommands = []string{"start", "stop", "lap", "reset", "help", "q"}
+
+// runStopwatch drives a Stopwatch from the commands the user types.
+func runStopwatch(_ context.Context, env *Env) error {
+	r, w := env.In, env.Out
+	sw := NewStopwatch(realClock{})
+	fmt.Fprintln(w, T("stopwatch.welcome"))
+	for {
+		fmt.Fprint(w, env.Theme.prompt(T("stopwatch.prompt")))
+		line, err := readLine(r)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch cmd := strings.TrimSpace(line); cmd {
+		case "":
+		case "q":
+			return nil
+		case "help":
+			fmt.Fprintln(w, translate("app.stopwatch.usage", stopwatchHelp))
+		case "start":
+			if err := sw.Start(); err != nil {
+				printError(w, err)
+				continue
+			}
+			fmt.Fprintln(w, T("stopwatch.started"))
+		case "lap":
+			split, err := sw.Lap()
+			if err != nil {
+				printError(w, err)
+				continue
+			}
+			env.Log.Info("lap", "split", split)
+			fmt.Fprintf(w, T("stopwatch.lap")+"\n", len(sw.Laps()), formatDuration(split))
+		case "stop":
+			total, err := sw.Stop()
+			if err != nil {
+				printError(w, err)
+				continue
+			}
+			env.Log.Info("stopped", "total", total)
+			fmt.Fprintf(w, T("stopwatch.stopped")+"\n", formatDuration(total))
+		case "reset":
+			sw.Reset()
+			fmt.Fprintln(w, T("stopwatch.reset"))
+		default:
+			printError(w, unknownCommand(cmd, stopwatchCommands))
+		}
+	}
+}
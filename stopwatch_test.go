@@ -0,0 +1,81 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestStopwatch(t *testing.T) {
+	c := newFakeClock()
+	sw := NewStopwatch(c)
+	if _, err := sw.Stop(); err != errNotRunning {
+		t.Errorf("Stop before Start: err = %v, want errNotRunning", err)
+	}
+	if _, err := sw.Lap(); err != errNotRunning {
+		t.Errorf("Lap before Start: err = %v, want errNotRunning", err)
+	}
+
+	if err := sw.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := sw.Start(); err != errRunning {
+		t.Errorf("second Start: err = %v, want errRunning", err)
+	}
+	c.Advance(1500 * time.Millisecond)
+	if lap, err := sw.Lap(); err != nil || lap != 1500*time.Millisecond {
+		t.Errorf("Lap() = %v, %v; want 1.5s", lap, err)
+	}
+	c.Advance(time.Second)
+	if lap, _ := sw.Lap(); lap != 2500*time.Millisecond {
+		t.Errorf("second Lap() = %v, want 2.5s", lap)
+	}
+	if total, err := sw.Stop(); err != nil || total != 2500*time.Millisecond {
+		t.Errorf("Stop() = %v, %v; want 2.5s", total, err)
+	}
+
+	// Time while stopped does not count.
+	c.Advance(time.Hour)
+	if got := sw.Elapsed(); got != 2500*time.Millisecond {
+		t.Errorf("Elapsed() while stopped = %v, want 2.5s", got)
+	}
+	sw.Start()
+	c.Advance(500 * time.Millisecond)
+	if got := sw.Elapsed(); got != 3*time.Second {
+		t.Errorf("Elapsed() after resuming = %v, want 3s", got)
+	}
+	if got, want := sw.Laps(), []time.Duration{1500 * time.Millisecond, 2500 * time.Millisecond}; !slices.Equal(got, want) {
+		t.Errorf("Laps() = %v, want %v", got, want)
+	}
+
+	sw.Reset()
+	if sw.Elapsed() != 0 || len(sw.Laps()) != 0 {
+		t.Errorf("after Reset: elapsed %v, laps %v", sw.Elapsed(), sw.Laps())
+	}
+	if err := sw.Start(); err != nil {
+		t.Errorf("Start after Reset: %v", err)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0.0s"},
+		{40 * time.Millisecond, "0.0s"},
+		{50 * time.Millisecond, "0.1s"},
+		{5300 * time.Millisecond, "5.3s"},
+		{59940 * time.Millisecond, "59.9s"},
+		{59960 * time.Millisecond, "1m00.0s"},
+		{65300 * time.Millisecond, "1m05.3s"},
+		{10*time.Minute + 30*time.Second, "10m30.0s"},
+		{time.Hour + 2*time.Minute + 3*time.Second, "1h02m03.0s"},
+		{25 * time.Hour, "25h00m00.0s"},
+	}
+	for _, tt := range tests {
+		if got := formatDuration(tt.d); got != tt.want {
+			t.Errorf("formatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import "unicode/utf8"
+
+// maxSuggestDistance is the most edits a mistyped command can be from a
+// known one for nearest to suggest it. Two covers a doubled, dropped or
+// swapped letter.
+const maxSuggestDistance = 2
+
+// levenshtein returns the number of single-character insertions, deletions
+// and substitutions that turn a into b, counting characters as runes.
+func levenshtein(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	// prev and cur are rows of the edit distance table: prev[j] is the
+	// distance from the first i-1 runes of s to the first j runes of t.
+	prev := make([]int, len(t)+1)
+	cur := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		cur[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(t)]
+}
+
+// nearest returns the command in known closest to cmd, and whether it is
+// within maxSuggestDistance edits and fewer edits than cmd has characters.
+// Without the second limit any word of one or two letters, such as "x",
+// would be taken for "q". Of equally close commands the first is returned.
+func nearest(cmd string, known []string) (string, bool) {
+	best, bestDist := "", maxSuggestDistance+1
+	for _, k := range known {
+		if d := levenshtein(cmd, k); d < bestDist {
+			best, bestDist = k, d
+		}
+	}
+	return best, bestDist <= maxSuggestDistance && bestDist < utf8.RuneCountInString(cmd)
+}
+
+// unknownCommand is the error for a command missing from known, suggesting
+// the nearest one if any is close.
+func unknownCommand(cmd string, known []string) error {
+	if k, ok := nearest(cmd, known); ok {
+		return newError("command.did_you_mean", cmd, k)
+	}
+	return newError("command.unknown", cmd)
+}
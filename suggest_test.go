@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "help", 4},
+		{"help", "", 4},
+		{"help", "help", 0},
+		{"hlp", "help", 1},
+		{"hepl", "help", 2},
+		{"halp", "help", 1},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		// Runes, not bytes: ñ for n is one substitution.
+		{"año", "ano", 1},
+		{"日本", "日本語", 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := levenshtein(tt.b, tt.a); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestNearest(t *testing.T) {
+	known := []string{"start", "stop", "lap", "reset", "help", "q"}
+	tests := []struct {
+		cmd  string
+		want string
+		ok   bool
+	}{
+		{"help", "help", true},
+		{"hlp", "help", true},
+		{"hepl", "help", true}, // two edits, the most allowed
+		{"strat", "start", true},
+		{"stp", "stop", true},
+		{"reste", "reset", true},
+		{"hxxxp", "help", false}, // three edits
+		{"zzzzzz", "", false},
+		// Not as many edits as the word has letters.
+		{"", "", false},
+		{"x", "", false},
+		{"1", "", false},
+		{"ab", "", false},
+		{"qq", "q", true},
+		{"hp", "help", false},
+		// Two edits from both start and stop: the first in known wins.
+		{"sta", "start", true},
+	}
+	for _, tt := range tests {
+		got, ok := nearest(tt.cmd, known)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("nearest(%q) = %q, %v; want %q, %v", tt.cmd, got, ok, tt.want, tt.ok)
+		}
+	}
+	if _, ok := nearest("help", nil); ok {
+		t.Error("nearest found a command in an empty list")
+	}
+}
+
+func TestUnknownCommand(t *testing.T) {
+	known := []string{"to", "from", "help", "q"}
+	if got, want := unknownCommand("hlp", known).Error(), `unknown command "hlp", did you mean "help"?`; got != want {
+		t.Errorf("unknownCommand(hlp) = %q, want %q", got, want)
+	}
+	if got, want := unknownCommand("convert", known).Error(), `unknown command "convert"`; got != want {
+		t.Errorf("unknownCommand(convert) = %q, want %q", got, want)
+	}
+	// Short words at the apps' prompts are not taken for q.
+	for _, tt := range []struct {
+		cmd   string
+		known []string
+	}{{"x", stopwatchCommands}, {"1", todoCommands}, {"x", encodeCommands}, {"7", mathToysCommands}, {"ab", romanCommands}} {
+		if got, want := unknownCommand(tt.cmd, tt.known).Error(), fmt.Sprintf("unknown command %q", tt.cmd); got != want {
+			t.Errorf("unknownCommand(%q, %q) = %q, want %q", tt.cmd, tt.known, got, want)
+		}
+	}
+}
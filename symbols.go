@@ -0,0 +1,75 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// SymbolTable holds the variables assigned during a calculator session.
+// A nil *SymbolTable has no variables and refuses assignments.
+type SymbolTable struct {
+	vars map[string]float64
+}
+
+// NewSymbolTable returns an empty SymbolTable.
+func NewSymbolTable() *SymbolTable {
+	return &SymbolTable{vars: map[string]float64{}}
+}
+
+// reserved reports whether name already means something in an expression.
+func reserved(name string) bool {
+	_, constant := constants[name]
+	_, function := functions[name]
+	return constant || function || name == "ans" || name == "xor" || strings.EqualFold(name, "mr")
+}
+
+// Set assigns v to name.
+func (s *SymbolTable) Set(name string, v float64) error {
+	if reserved(name) {
+		return newError("eval.reserved", name)
+	}
+	if s == nil {
+		return newError("eval.no_vars", name)
+	}
+	s.vars[name] = v
+	return nil
+}
+
+// clone returns a copy of s that shares nothing with it.
+func (s *SymbolTable) clone() *SymbolTable {
+	if s == nil {
+		return nil
+	}
+	return &SymbolTable{vars: maps.Clone(s.vars)}
+}
+
+// equal reports whether s and t hold the same variables.
+func (s *SymbolTable) equal(t *SymbolTable) bool {
+	if s == nil || t == nil {
+		return s == t
+	}
+	return maps.Equal(s.vars, t.vars)
+}
+
+// Get returns the value of name and whether it has been assigned.
+func (s *SymbolTable) Get(name string) (float64, bool) {
+	if s == nil {
+		return 0, false
+	}
+	v, ok := s.vars[name]
+	return v, ok
+}
+
+// Names returns the assigned names in alphabetical order.
+func (s *SymbolTable) Names() []string {
+	if s == nil {
+		return nil
+	}
+	names := make([]string, 0, len(s.vars))
+	for name := range s.vars {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSymbolTable(t *testing.T) {
+	s := NewSymbolTable()
+	if err := s.Set("x", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("rate", 0.5); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("x", 4); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := s.Get("x"); !ok || v != 4 {
+		t.Errorf(`Get("x") = %v, %v; want 4, true`, v, ok)
+	}
+	if _, ok := s.Get("y"); ok {
+		t.Error(`Get("y") found a variable that was never assigned`)
+	}
+	if got := s.Names(); !slices.Equal(got, []string{"rate", "x"}) {
+		t.Errorf("Names() = %q", got)
+	}
+
+	for _, name := range []string{"pi", "e", "sin", "fact", "ans", "xor", "mr", "MR"} {
+		if err := s.Set(name, 1); err == nil || err.Error() != `cannot assign to reserved name "`+name+`"` {
+			t.Errorf("Set(%q) error = %v", name, err)
+		}
+	}
+
+	var none *SymbolTable
+	if err := none.Set("x", 1); err == nil {
+		t.Error("a nil table accepted an assignment")
+	}
+	if _, ok := none.Get("x"); ok || none.Names() != nil {
+		t.Error("a nil table has variables")
+	}
+}
+
+func TestCalculatorVariables(t *testing.T) {
+	c := NewCalculator()
+	steps := []struct {
+		expr, want, err string
+	}{
+		{"x = 3", "3", ""},
+		{"x * 2", "6", ""},
+		{"y = x + 1", "4", ""},
+		{"x = x * y", "12", ""},
+		{"x", "12", ""},
+		{"z + 1", "", "undefined variable: z"},
+		{"pi = 3", "", `cannot assign to reserved name "pi"`},
+		{"pi", "3.141592653589793", ""},
+	}
+	for _, s := range steps {
+		got, _, err := c.Calculate(s.expr)
+		if s.err != "" {
+			if err == nil || err.Error() != s.err {
+				t.Errorf("Calculate(%q) error = %v, want %q", s.expr, err, s.err)
+			}
+			continue
+		}
+		if err != nil || got != s.want {
+			t.Errorf("Calculate(%q) = %q, %v; want %q", s.expr, got, err, s.want)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register(App{
+		ID:          "todo",
+		Name:        "To-Do List",
+		Description: "keep a list of tasks, saved between runs",
+		Usage:       todoHelp,
+		Run:         runTodo,
+	})
+}
+
+// Task is one item on the to-do list.
+type Task struct {
+	ID      int       `json:"id"`
+	Text    string    `json:"text"`
+	Done    bool      `json:"done"`
+	Created time.Time `json:"created"`
+}
+
+// TodoList is an ordered list of tasks whose changes can be undone.
+type TodoList struct {
+	Tasks   []Task
+	changes UndoStack[[]Task]
+}
+
+// record is deferred with a copy of the tasks taken before a change, and
+// makes the change undoable if there was one.
+func (l *TodoList) record(before []Task) {
+	if !slices.Equal(before, l.Tasks) {
+		l.changes.Push(before)
+	}
+}
+
+// Undo reverts the latest add, done or remove that has not been undone.
+func (l *TodoList) Undo() error {
+	tasks, err := l.changes.Undo(slices.Clone(l.Tasks))
+	if err != nil {
+		return err
+	}
+	l.Tasks = tasks
+	return nil
+}
+
+// Redo makes again the change the latest Undo reverted.
+func (l *TodoList) Redo() error {
+	tasks, err := l.changes.Redo(slices.Clone(l.Tasks))
+	if err != nil {
+		return err
+	}
+	l.Tasks = tasks
+	return nil
+}
+
+// maxID returns the highest ID in use, or 0 if there are no tasks.
+func (l *TodoList) maxID() int {
+	id := 0
+	for _, t := range l.Tasks {
+		id = max(id, t.ID)
+	}
+	return id
+}
+
+// Add appends a task numbered one past the highest ID in use and returns
+// it.
+func (l *TodoList) Add(text string) Task {
+	defer l.record(slices.Clone(l.Tasks))
+	t := Task{ID: l.maxID() + 1, Text: text, Created: time.Now()}
+	l.Tasks = append(l.Tasks, t)
+	return t
+}
+
+// Done marks the task with the given ID as done.
+func (l *TodoList) Done(id int) error {
+	defer l.record(slices.Clone(l.Tasks))
+	i, err := l.index(id)
+	if err != nil {
+		return err
+	}
+	l.Tasks[i].Done = true
+	return nil
+}
+
+// Remove deletes the task with the given ID.
+func (l *TodoList) Remove(id int) error {
+	defer l.record(slices.Clone(l.Tasks))
+	i, err := l.index(id)
+	if err != nil {
+		return err
+	}
+	l.Tasks = append(l.Tasks[:i], l.Tasks[i+1:]...)
+	return nil
+}
+
+func (l *TodoList) index(id int) (int, error) {
+	for i, t := range l.Tasks {
+		if t.ID == id {
+			return i, nil
+		}
+	}
+	return 0, newError("todo.no_task", id)
+}
+
+// LoadTodos reads tasks saved by SaveTodos. A missing file gives an empty
+// list.
+func LoadTodos(path string) ([]Task, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var tasks []Task
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return nil, fmt.Errorf(T("todo.invalid"), path, err)
+	}
+	return tasks, nil
+}
+
+// SaveTodos writes tasks to path as indented JSON, replacing the file
+// atomically.
+func SaveTodos(path string, tasks []Task) error {
+	if tasks == nil {
+		tasks = []Task{} // write [] rather than null
+	}
+	data, err := json.MarshalIndent(tasks, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+// todoHelp is the to-do list's usage, printed by its "help" command.
+const todoHelp = `Commands:
+  add <text>   add a task
+  done [n]     mark task n as done, asking for n if it is left out
+  rm [n]       remove task n, asking for n if it is left out
+  list         show the tasks
+  undo, redo   take back the latest change, or make it again
+  export <f>   write the tasks to the CSV file f
+  help         show this text
+  q            back to the menu`
+
+// todoCommands are the commands runTodo knows.
+var todoCommands = []string{"add", "done", "rm", "list", "undo", "redo", "export", "help", "q"}
+
+// taskCSVHeader names the columns of taskRows.
+var taskCSVHeader = []string{"id", "text", "done", "created"}
+
+// taskRows turns tasks into CSV rows.
+func taskRows(tasks []Task) [][]string {
+	rows := make([][]string, len(tasks))
+	for i, t := range tasks {
+		rows[i] = []string{strconv.Itoa(t.ID), t.Text, strconv.FormatBool(t.Done), t.Created.Format(time.RFC3339)}
+	}
+	return rows
+}
+
+// runTodo manages the to-do list in ~/.demo-go/todos.json, saving after
+// every change.
+func runTodo(_ context.Context, env *Env) error {
+	r, w := env.In, env.Out
+	path, err := dataPath("todos.json")
+	if err != nil {
+		return err
+	}
+	tasks, err := LoadTodos(path)
+	if err != nil {
+		return err
+	}
+	list := &TodoList{Tasks: tasks}
+
+	fmt.Fprintln(w, T("todo.welcome"))
+	printTasks(w, list.Tasks)
+	for {
+		fmt.Fprint(w, env.Theme.prompt(T("todo.prompt")))
+		line, err := readLine(r)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		cmd, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+		arg = strings.TrimSpace(arg)
+
+		switch cmd {
+		case "":
+			continue
+		case "q":
+			return nil
+		case "help":
+			fmt.Fprintln(w, translate("app.todo.usage", todoHelp))
+			continue
+		case "list":
+			printTasks(w, list.Tasks)
+			continue
+		case "add":
+			if arg == "" {
+				printError(w, newError("todo.add_usage"))
+				continue
+			}
+			list.Add(arg)
+		case "export":
+			if arg == "" {
+				printError(w, newError("export.usage"))
+				continue
+			}
+			if err := writeCSV(arg, taskCSVHeader, taskRows(list.Tasks)); err != nil {
+				printError(w, err)
+				continue
+			}
+			fmt.Fprintf(w, T("export.done")+"\n", len(list.Tasks), arg)
+			continue
+		case "undo":
+			if err := list.Undo(); err != nil {
+				printError(w, err)
+				continue
+			}
+		case "redo":
+			if err := list.Redo(); err != nil {
+				printError(w, err)
+				continue
+			}
+		case "done", "rm":
+			if len(list.Tasks) == 0 {
+				printError(w, newError("todo.no_tasks"))
+				continue
+			}
+			id, ok, err := intArg(r, w, arg, T("todo.task_number"), 1, list.maxID())
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			if cmd == "done" {
+				err = list.Done(id)
+			} else {
+				err = list.Remove(id)
+			}
+			if err != nil {
+				printError(w, err)
+				continue
+			}
+		default:
+			printError(w, unknownCommand(cmd, todoCommands))
+			continue
+		}
+
+		// Only commands that changed the list get this far.
+		env.Log.Info("todo", "command", cmd, "arg", arg)
+		if err := SaveTodos(path, list.Tasks); err != nil {
+			printError(w, err)
+		}
+		printTasks(w, list.Tasks)
+	}
+}
+
+// printTasks lists tasks with their IDs and whether they are done.
+func printTasks(w io.Writer, tasks []Task) {
+	if len(tasks) == 0 {
+		fmt.Fprintln(w, T("todo.nothing"))
+		return
+	}
+	for _, t := range tasks {
+		mark := " "
+		if t.Done {
+			mark = "x"
+		}
+		fmt.Fprintf(w, "%d. [%s] %s\n", t.ID, mark, t.Text)
+	}
+}
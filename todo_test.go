@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTodoList(t *testing.T) {
+	var l TodoList
+	if got := l.Add("buy milk"); got.ID != 1 || got.Text != "buy milk" || got.Done {
+		t.Errorf("first Add = %+v", got)
+	}
+	l.Add("walk the dog")
+	l.Add("write tests")
+
+	if err := l.Done(2); err != nil {
+		t.Fatal(err)
+	}
+	if !l.Tasks[1].Done || l.Tasks[0].Done || l.Tasks[2].Done {
+		t.Errorf("Done(2) marked the wrong tasks: %+v", l.Tasks)
+	}
+
+	if err := l.Remove(1); err != nil {
+		t.Fatal(err)
+	}
+	if len(l.Tasks) != 2 || l.Tasks[0].ID != 2 || l.Tasks[1].ID != 3 {
+		t.Errorf("after Remove(1) the tasks are %+v", l.Tasks)
+	}
+	// New tasks are numbered past the highest ID, not the count.
+	if got := l.Add("read"); got.ID != 4 {
+		t.Errorf("Add after a removal got ID %d, want 4", got.ID)
+	}
+
+	for _, id := range []int{0, 1, 99} {
+		if err := l.Done(id); err == nil || err.Error() != fmt.Sprintf("no task %d", id) {
+			t.Errorf("Done(%d) error = %v", id, err)
+		}
+		if err := l.Remove(id); err == nil {
+			t.Errorf("Remove(%d) succeeded", id)
+		}
+	}
+	if len(l.Tasks) != 3 {
+		t.Errorf("failed changes changed the list: %+v", l.Tasks)
+	}
+}
+
+func TestTodosRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todos.json")
+	if tasks, err := LoadTodos(path); err != nil || tasks != nil {
+		t.Fatalf("LoadTodos of a missing file = %v, %v; want no tasks", tasks, err)
+	}
+
+	created := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	want := []Task{
+		{ID: 1, Text: "buy milk", Created: created},
+		{ID: 3, Text: `say "hi", then leave`, Done: true, Created: created.Add(time.Hour)},
+	}
+	if err := SaveTodos(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadTodos(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("LoadTodos = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].ID != want[i].ID || got[i].Text != want[i].Text || got[i].Done != want[i].Done || !got[i].Created.Equal(want[i].Created) {
+			t.Errorf("task %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if err := SaveTodos(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "[]\n" {
+		t.Errorf("an empty list is saved as %q, want []", data)
+	}
+
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTodos(path); err == nil {
+		t.Error("LoadTodos accepted a broken file")
+	}
+}
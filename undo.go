@@ -0,0 +1,48 @@
+package main
+
+// Errors returned when an UndoStack is empty in the direction asked for.
+var (
+	errNothingToUndo = newError("undo.nothing")
+	errNothingToRedo = newError("redo.nothing")
+)
+
+// UndoStack keeps snapshots of some state so that changes to it can be
+// undone and redone. The snapshots must not share memory with the live
+// state, so a T holding slices or maps needs copying before it is pushed.
+type UndoStack[T any] struct {
+	undo []T
+	redo []T
+}
+
+// Push records before, the state just before a change. Anything that
+// could be redone is forgotten, since it followed the state being replaced.
+func (s *UndoStack[T]) Push(before T) {
+	s.undo = append(s.undo, before)
+	s.redo = nil
+}
+
+// Undo returns the state before the latest change and remembers current
+// so that Redo can return to it.
+func (s *UndoStack[T]) Undo(current T) (T, error) {
+	if len(s.undo) == 0 {
+		var zero T
+		return zero, errNothingToUndo
+	}
+	prev := s.undo[len(s.undo)-1]
+	s.undo = s.undo[:len(s.undo)-1]
+	s.redo = append(s.redo, current)
+	return prev, nil
+}
+
+// Redo returns the state the latest Undo left and remembers current so
+// that Undo can return to it.
+func (s *UndoStack[T]) Redo(current T) (T, error) {
+	if len(s.redo) == 0 {
+		var zero T
+		return zero, errNothingToRedo
+	}
+	next := s.redo[len(s.redo)-1]
+	s.redo = s.redo[:len(s.redo)-1]
+	s.undo = append(s.undo, current)
+	return next, nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import "testing"
+
+func TestUndoStack(t *testing.T) {
+	var s UndoStack[int]
+	if _, err := s.Undo(0); err != errNothingToUndo {
+		t.Errorf("Undo on an empty stack: err = %v, want errNothingToUndo", err)
+	}
+	if _, err := s.Redo(0); err != errNothingToRedo {
+		t.Errorf("Redo on an empty stack: err = %v, want errNothingToRedo", err)
+	}
+
+	// The state goes 1, 2, 3, each change pushing the state before it.
+	state := 1
+	change := func(to int) {
+		s.Push(state)
+		state = to
+	}
+	step := func(f func(int) (int, error), want int) {
+		t.Helper()
+		got, err := f(state)
+		if err != nil || got != want {
+			t.Fatalf("got %d, %v; want %d", got, err, want)
+		}
+		state = got
+	}
+	change(2)
+	change(3)
+
+	// Undo walks back in reverse order and Redo forward again.
+	step(s.Undo, 2)
+	step(s.Undo, 1)
+	if _, err := s.Undo(state); err != errNothingToUndo {
+		t.Errorf("Undo past the first change: err = %v", err)
+	}
+	step(s.Redo, 2)
+	step(s.Redo, 3)
+	if _, err := s.Redo(state); err != errNothingToRedo {
+		t.Errorf("Redo past the latest change: err = %v", err)
+	}
+
+	// A new change after an undo forgets what could have been redone.
+	step(s.Undo, 2)
+	change(4)
+	if _, err := s.Redo(state); err != errNothingToRedo {
+		t.Errorf("Redo after a new change: err = %v, want errNothingToRedo", err)
+	}
+	step(s.Undo, 2)
+	step(s.Undo, 1)
+	step(s.Redo, 2)
+	step(s.Redo, 4)
+}
+
+func TestTodoUndo(t *testing.T) {
+	var l TodoList
+	l.Add("a")
+	l.Add("b")
+	l.Done(1)
+	if err := l.Undo(); err != nil || l.Tasks[0].Done {
+		t.Fatalf("Undo of done: %v, tasks %+v", err, l.Tasks)
+	}
+	if err := l.Undo(); err != nil || len(l.Tasks) != 1 {
+		t.Fatalf("Undo of add: %v, tasks %+v", err, l.Tasks)
+	}
+	if err := l.Redo(); err != nil || len(l.Tasks) != 2 {
+		t.Fatalf("Redo of add: %v, tasks %+v", err, l.Tasks)
+	}
+	// A failed change records nothing.
+	l.Remove(99)
+	if err := l.Redo(); err != nil || !l.Tasks[0].Done {
+		t.Errorf("Redo of done after a failed remove: %v, tasks %+v", err, l.Tasks)
+	}
+}
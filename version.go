@@ -0,0 +1,17 @@
+package main
+
+import "fmt"
+
+// Build metadata, set when building a release with
+//
+//	go build -ldflags "-X main.version=1.2.0 -X main.commit=$(git rev-parse --short HEAD) -X main.date=$(date -u +%Y-%m-%d)"
+var (
+	version = "dev"
+	commit  = "none"
+	date    = "unknown"
+)
+
+// versionString describes the build for -version and the version command.
+func versionString() string {
+	return fmt.Sprintf("demo-go %s (commit %s, built %s)", version, commit, date)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestVersionString(t *testing.T) {
+	if got, want := versionString(), "demo-go dev (commit none, built unknown)"; got != want {
+		t.Errorf("versionString() = %q, want %q", got, want)
+	}
+
+	saved := [3]string{version, commit, date}
+	t.Cleanup(func() { version, commit, date = saved[0], saved[1], saved[2] })
+	version, commit, date = "1.2.0", "abc1234", "2024-05-01"
+	const want = "demo-go 1.2.0 (commit abc1234, built 2024-05-01)"
+	if got := versionString(); got != want {
+		t.Errorf("versionString() = %q, want %q", got, want)
+	}
+	checkInOrder(t, runMenu(t, defaultConfig, menuInput("version\n{Exit}\n")), want+"\n", "Choose app:", "Exited")
+}
+
+// TestVersionFlag runs the test binary again as the program itself, since
+// -version ends the process.
+func TestVersionFlag(t *testing.T) {
+	if os.Getenv("DEMO_RUN_MAIN") == "1" {
+		os.Args = []string{os.Args[0], "-version"}
+		os.Exit(runMain())
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestVersionFlag$")
+	cmd.Env = append(os.Environ(), "DEMO_RUN_MAIN=1")
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("-version: %v", err)
+	}
+	if got, want := string(out), versionString()+"\n"; got != want {
+		t.Errorf("-version printed %q, want %q", got, want)
+	}
+}
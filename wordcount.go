@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+func init() {
+	Register(App{
+		ID:          "wordcount",
+		Name:        "Word Count",
+		Description: "count the lines, words, characters and bytes of some text, like wc",
+		Usage:       wordCountHelp,
+		Run:         runWordCount,
+	})
+}
+
+// wordCountHelp is the app's usage, printed by its "help" command.
+const wordCountHelp = `Type or paste some text, then a line with only "." to count it.
+Each line counts with its line ending, as wc does. The longest line is
+measured in characters without its ending. Type help or q as the first
+line of a text for this help or to go back to the menu.`
+
+// textCounts is what wc-style counting finds in some text.
+type textCounts struct {
+	Lines   int
+	Words   int // runs of non-space characters, as strings.Fields splits them
+	Chars   int // runes, line endings included
+	Bytes   int // line endings included
+	Longest int // runes in the longest line, without its ending
+}
+
+// add counts one line of text, given without its line ending.
+func (c *textCounts) add(line string) {
+	runes := utf8.RuneCountInString(line)
+	c.Lines++
+	c.Words += len(strings.Fields(line))
+	c.Chars += runes + 1
+	c.Bytes += len(line) + 1
+	c.Longest = max(c.Longest, runes)
+}
+
+// countText counts the lines of text, each given without its line ending.
+func countText(lines []string) textCounts {
+	var c textCounts
+	for _, line := range lines {
+		c.add(line)
+	}
+	return c
+}
+
+func printTextCounts(w io.Writer, c textCounts) {
+	printFields(w, []field{
+		{"wordcount.lines", strconv.Itoa(c.Lines)},
+		{"wordcount.words", strconv.Itoa(c.Words)},
+		{"wordcount.chars", strconv.Itoa(c.Chars)},
+		{"wordcount.bytes", strconv.Itoa(c.Bytes)},
+		{"wordcount.longest", strconv.Itoa(c.Longest)},
+	})
+}
+
+// runWordCount reads texts, each ended by a line with only ".", and prints
+// their counts, until the user types "q" to start a text or the input
+// ends.
+func runWordCount(_ context.Context, env *Env) error {
+	r, w := env.In, env.Out
+	fmt.Fprintln(w, T("wordcount.welcome"))
+	var c textCounts
+	started := false // whether the text has a line yet, so q and help are text
+	for {
+		line, err := readLine(r)
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if !started {
+			switch strings.TrimSpace(line) {
+			case "q":
+				return nil
+			case "help":
+				fmt.Fprintln(w, translate("app.wordcount.usage", wordCountHelp))
+				continue
+			}
+		}
+		if err == io.EOF || strings.TrimSpace(line) == "." {
+			if started || err != io.EOF {
+				env.Log.Info("wordcount", "lines", c.Lines, "words", c.Words)
+				printTextCounts(w, c)
+			}
+			if err == io.EOF {
+				return nil
+			}
+			c, started = textCounts{}, false
+			continue
+		}
+		c.add(line)
+		started = true
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestCountText(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  textCounts
+	}{
+		{"empty", nil, textCounts{}},
+		{"one blank line", []string{""}, textCounts{Lines: 1, Chars: 1, Bytes: 1}},
+		{"ascii", []string{"hello world", "go"}, textCounts{Lines: 2, Words: 3, Chars: 15, Bytes: 15, Longest: 11}},
+		// Five runes in seven bytes, and a rune of four bytes.
+		{"multibyte", []string{"ñandú", "日本 語", "🙂"}, textCounts{Lines: 3, Words: 4, Chars: 13, Bytes: 24, Longest: 5}},
+		// Trailing and inner spaces and tabs count as characters, not words.
+		{"trailing whitespace", []string{"a  b \t ", "   "}, textCounts{Lines: 2, Words: 2, Chars: 12, Bytes: 12, Longest: 7}},
+	}
+	for _, tt := range tests {
+		if got := countText(tt.lines); got != tt.want {
+			t.Errorf("%s: countText(%q) = %+v, want %+v", tt.name, tt.lines, got, tt.want)
+		}
+	}
+}
+
+func TestRunWordCount(t *testing.T) {
+	var out bytes.Buffer
+	env := &Env{In: strings.NewReader("ñandú  \nhola\n.\n.\nq\n"), Out: &out, Log: slog.New(discardHandler{})}
+	if err := runWordCount(context.Background(), env); err != nil {
+		t.Fatal(err)
+	}
+	// The second text is empty, and q after it goes back.
+	checkInOrder(t, out.String(),
+		"lines    2\nwords    2\nchars    13\nbytes    15\nlongest  7\n",
+		"lines    0\nwords    0\nchars    0\nbytes    0\nlongest  0\n")
+	if strings.Count(out.String(), "lines") != 2 {
+		t.Errorf("counted other than two texts:\n%s", out.String())
+	}
+}